@@ -0,0 +1,240 @@
+// Package clitest provides helpers for testing command line programs built with cli.
+//
+// It takes care of the boilerplate of capturing a command's output, isolating it from
+// the environment of the test process and comparing help text against snapshots, so that
+// a full invocation of a CLI can be tested in one line:
+//
+//	func TestServe(t *testing.T) {
+//		result := clitest.Run(t, BuildRoot, "serve", "--port", "8080")
+//		test.Ok(t, result.Err)
+//		test.Equal(t, result.Stdout, "listening on :8080\n")
+//	}
+//
+// Commands are executed in-process through [cli.Command.Execute], no binary is built.
+package clitest // import "go.followtheprocess.codes/cli/clitest"
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"go.followtheprocess.codes/cli"
+	"go.followtheprocess.codes/cli/internal/override"
+	"go.followtheprocess.codes/snapshot"
+)
+
+// Result is the outcome of running a command with [Run] or [RunWith].
+type Result struct {
+	// Err is the error returned from [cli.Command.Execute], if any.
+	Err error
+
+	// Stdout is everything the command wrote to stdout.
+	Stdout string
+
+	// Stderr is everything the command wrote to stderr.
+	Stderr string
+
+	// ExitCode is the exit code a program would conventionally exit with given Err.
+	//
+	// It is 0 if Err is nil. If Err (or any error it wraps) has an ExitCode() int
+	// method, like [os/exec.ExitError], that is used. Otherwise it is 1.
+	ExitCode int
+}
+
+// Option is a functional option for configuring how [RunWith] runs a command.
+type Option func(cfg *config)
+
+// config holds the configuration for RunWith.
+type config struct {
+	stdin       io.Reader
+	env         map[string]string
+	order       []string // Keys of env in the order they were given, so Setenv is deterministic
+	interactive bool
+}
+
+// Stdin is an [Option] that sets the command's stdin to r.
+//
+// Without this option the command reads from an empty stdin.
+func Stdin(r io.Reader) Option {
+	return func(cfg *config) {
+		cfg.stdin = r
+	}
+}
+
+// Interactive is an [Option] that treats the command's stdin as interactive, so flags
+// and arguments that prompt for their value read the answers from the reader given to
+// [Stdin] (see [cli.Interactive]).
+//
+// Without this option stdin is never interactive, so nothing prompts.
+func Interactive() Option {
+	return func(cfg *config) {
+		cfg.interactive = true
+	}
+}
+
+// Env is an [Option] that sets an environment variable for the duration of the test.
+//
+// Every environment variable read by a flag in the command tree (see [cli.Env]), along
+// with those controlling cli itself such as $NO_PAGER, $PAGER and the tool's own pager
+// variable, is unset before the command runs so tests are isolated from the environment
+// they happen to run in, this option is how to set one explicitly.
+func Env(key, value string) Option {
+	return func(cfg *config) {
+		if cfg.env == nil {
+			cfg.env = make(map[string]string)
+		}
+
+		if _, exists := cfg.env[key]; !exists {
+			cfg.order = append(cfg.order, key)
+		}
+
+		cfg.env[key] = value
+	}
+}
+
+// Run builds the command returned by builder and executes it with args, returning
+// the captured output and any error.
+//
+// The command's stdout and stderr are captured, it reads from an empty stdin, and any
+// environment variables read by its flags or by cli itself are unset for the duration
+// of the test.
+//
+// Because Run sets environment variables with [testing.T.Setenv], it cannot be
+// used in parallel tests.
+//
+// Run fails the test immediately if builder returns an error, errors from executing
+// the command are returned in [Result].
+func Run(tb testing.TB, builder cli.Builder, args ...string) Result {
+	tb.Helper()
+
+	return RunWith(tb, builder, args)
+}
+
+// RunWith is like [Run] but accepts a number of options to configure things like
+// stdin and environment variables.
+//
+//	result := clitest.RunWith(t, BuildRoot, []string{"login"},
+//		clitest.Stdin(strings.NewReader("hunter2\n")),
+//		clitest.Interactive(),
+//		clitest.Env("MYTOOL_USER", "me"),
+//	)
+func RunWith(tb testing.TB, builder cli.Builder, args []string, options ...Option) Result {
+	tb.Helper()
+
+	cfg := config{stdin: strings.NewReader("")}
+	for _, option := range options {
+		option(&cfg)
+	}
+
+	if builder == nil {
+		tb.Fatal("clitest: builder must not be nil")
+
+		return Result{}
+	}
+
+	cmd, err := builder()
+	if err != nil {
+		tb.Fatalf("clitest: could not build command: %v", err)
+
+		return Result{}
+	}
+
+	// Isolate the command from whatever its flags' env vars (and cli's own) happen to be set to
+	for _, name := range override.EnvVars(cmd) {
+		tb.Setenv(name, "")
+		os.Unsetenv(name)
+	}
+
+	for _, key := range cfg.order {
+		tb.Setenv(key, cfg.env[key])
+	}
+
+	if args == nil {
+		args = []string{}
+	}
+
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+
+	err = override.Apply(cmd, override.Config{
+		Stdin:       cfg.stdin,
+		Stdout:      stdout,
+		Stderr:      stderr,
+		Args:        args,
+		Interactive: cfg.interactive,
+	})
+	if err != nil {
+		tb.Fatalf("clitest: %v", err)
+
+		return Result{}
+	}
+
+	err = cmd.Execute(tb.Context())
+
+	return Result{
+		Err:      err,
+		Stdout:   stdout.String(),
+		Stderr:   stderr.String(),
+		ExitCode: exitCode(err),
+	}
+}
+
+// Help returns the help text for the command at path beneath the root command
+// returned by builder, e.g. Help(t, BuildRoot, "serve", "start") is the output
+// of 'mytool serve start --help'.
+//
+// It fails the test if showing the help returns an error.
+func Help(tb testing.TB, builder cli.Builder, path ...string) string {
+	tb.Helper()
+
+	args := make([]string, 0, len(path)+1)
+	args = append(args, path...)
+	args = append(args, "--help")
+
+	result := Run(tb, builder, args...)
+	if result.Err != nil {
+		tb.Fatalf("clitest: showing help for %v returned an error: %v", path, result.Err)
+	}
+
+	return result.Stderr
+}
+
+// SnapHelp compares the help text for the command at path beneath the root command
+// returned by builder against a snapshot stored under testdata/snapshots, named
+// after the test.
+//
+// Any snapshot options are passed through, for example to update the snapshots
+// from a test flag:
+//
+//	var update = flag.Bool("update", false, "Update snapshots")
+//
+//	func TestHelp(t *testing.T) {
+//		clitest.SnapHelp(t, BuildRoot, []string{"serve"}, snapshot.Update(*update))
+//	}
+func SnapHelp(tb testing.TB, builder cli.Builder, path []string, options ...snapshot.Option) {
+	tb.Helper()
+
+	opts := make([]snapshot.Option, 0, len(options)+1)
+	opts = append(opts, snapshot.WithFormatter(snapshot.TextFormatter()))
+	opts = append(opts, options...)
+
+	snap := snapshot.New(tb, opts...)
+	snap.Snap(Help(tb, builder, path...))
+}
+
+// exitCode returns the conventional exit code for err.
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	var coder interface{ ExitCode() int }
+	if errors.As(err, &coder) {
+		return coder.ExitCode()
+	}
+
+	return 1
+}
@@ -0,0 +1,215 @@
+package clitest_test
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"go.followtheprocess.codes/cli"
+	"go.followtheprocess.codes/cli/clitest"
+	"go.followtheprocess.codes/snapshot"
+	"go.followtheprocess.codes/test"
+)
+
+var update = flag.Bool("update", false, "Update snapshots and testdata")
+
+// exitError is an error that carries its own exit code.
+type exitError struct {
+	code int
+}
+
+func (e exitError) Error() string { return fmt.Sprintf("exit status %d", e.code) }
+func (e exitError) ExitCode() int { return e.code }
+
+// build returns a small but realistic command tree for testing.
+func build() (*cli.Command, error) {
+	var options struct {
+		name  string
+		count int
+	}
+
+	greet, err := cli.New(
+		"greet",
+		cli.Short("Say hello"),
+		cli.Flag(&options.name, "name", 'n', "Who to greet", cli.FlagDefault("world"), cli.Env[string]("GREETER_NAME")),
+		cli.Run(func(ctx context.Context, cmd *cli.Command) error {
+			fmt.Fprintf(cmd.Stdout(), "hello %s\n", options.name)
+			fmt.Fprintln(cmd.Stderr(), "greeted")
+
+			return nil
+		}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	echo, err := cli.New(
+		"echo",
+		cli.Short("Copy stdin to stdout"),
+		cli.Run(func(ctx context.Context, cmd *cli.Command) error {
+			_, err := io.Copy(cmd.Stdout(), cmd.Stdin())
+			return err
+		}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	fail, err := cli.New(
+		"fail",
+		cli.Short("Always fail"),
+		cli.Flag(&options.count, "code", 'c', "Exit code to fail with"),
+		cli.Run(func(ctx context.Context, cmd *cli.Command) error {
+			if options.count != 0 {
+				return fmt.Errorf("wrapped: %w", exitError{code: options.count})
+			}
+
+			return errors.New("bang")
+		}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return cli.New(
+		"greeter",
+		cli.Short("A friendly tool"),
+		cli.SubCommands(
+			func() (*cli.Command, error) { return greet, nil },
+			func() (*cli.Command, error) { return echo, nil },
+			func() (*cli.Command, error) { return fail, nil },
+		),
+	)
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name     string   // Name of the test case
+		stdout   string   // Expected stdout
+		stderr   string   // Expected stderr
+		args     []string // Args to run with
+		exitCode int      // Expected exit code
+		wantErr  bool     // Whether we want an error
+	}{
+		{
+			name:     "success",
+			args:     []string{"greet"},
+			stdout:   "hello world\n",
+			stderr:   "greeted\n",
+			exitCode: 0,
+			wantErr:  false,
+		},
+		{
+			name:     "with flags",
+			args:     []string{"greet", "--name", "tests"},
+			stdout:   "hello tests\n",
+			stderr:   "greeted\n",
+			exitCode: 0,
+			wantErr:  false,
+		},
+		{
+			name:     "plain error",
+			args:     []string{"fail"},
+			exitCode: 1,
+			wantErr:  true,
+		},
+		{
+			name:     "exit code error",
+			args:     []string{"fail", "--code", "3"},
+			exitCode: 3,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := clitest.Run(t, build, tt.args...)
+			test.WantErr(t, result.Err, tt.wantErr)
+			test.Equal(t, result.ExitCode, tt.exitCode)
+
+			if !tt.wantErr {
+				test.Equal(t, result.Stdout, tt.stdout)
+				test.Equal(t, result.Stderr, tt.stderr)
+			}
+		})
+	}
+}
+
+func TestRunEnvIsolation(t *testing.T) {
+	// Something leaking in from the environment the tests run in
+	t.Setenv("GREETER_NAME", "leaked")
+
+	// Along with the variables that control cli itself
+	name, version, _ := strings.Cut(cli.DescribeEnv, "=")
+	t.Setenv(name, version)
+
+	for _, key := range []string{"NO_PAGER", "PAGER", "GREETER_PAGER"} {
+		t.Setenv(key, "leaked")
+	}
+
+	result := clitest.Run(t, build, "greet")
+	test.Ok(t, result.Err)
+	test.Equal(t, result.Stdout, "hello world\n")
+
+	for _, key := range []string{name, "NO_PAGER", "PAGER", "GREETER_PAGER"} {
+		_, ok := os.LookupEnv(key)
+		test.False(t, ok)
+	}
+
+	result = clitest.RunWith(t, build, []string{"greet"}, clitest.Env("GREETER_NAME", "env"))
+	test.Ok(t, result.Err)
+	test.Equal(t, result.Stdout, "hello env\n")
+}
+
+func TestRunStdin(t *testing.T) {
+	result := clitest.RunWith(t, build, []string{"echo"}, clitest.Stdin(strings.NewReader("piped in\n")))
+	test.Ok(t, result.Err)
+	test.Equal(t, result.Stdout, "piped in\n")
+
+	// Default is an empty stdin, not the test process's
+	result = clitest.Run(t, build, "echo")
+	test.Ok(t, result.Err)
+	test.Equal(t, result.Stdout, "")
+}
+
+func TestRunInteractive(t *testing.T) {
+	login := func() (*cli.Command, error) {
+		var user string
+
+		return cli.New(
+			"login",
+			cli.Flag(&user, "user", 'u', "User to log in as", cli.Prompt[string]()),
+			cli.Run(func(ctx context.Context, cmd *cli.Command) error {
+				fmt.Fprintf(cmd.Stdout(), "logged in as %q\n", user)
+				return nil
+			}),
+		)
+	}
+
+	result := clitest.RunWith(t, login, nil, clitest.Stdin(strings.NewReader("me\n")), clitest.Interactive())
+	test.Ok(t, result.Err)
+	test.Equal(t, result.Stdout, "logged in as \"me\"\n")
+
+	// Without opting in, stdin is not a terminal so nothing prompts
+	result = clitest.RunWith(t, login, nil, clitest.Stdin(strings.NewReader("me\n")))
+	test.Ok(t, result.Err)
+	test.Equal(t, result.Stdout, "logged in as \"\"\n")
+}
+
+func TestHelp(t *testing.T) {
+	help := clitest.Help(t, build, "greet")
+	test.True(t, strings.Contains(help, "Say hello"))
+	test.True(t, strings.Contains(help, "--name"))
+
+	root := clitest.Help(t, build)
+	test.True(t, strings.Contains(root, "A friendly tool"))
+}
+
+func TestSnapHelp(t *testing.T) {
+	clitest.SnapHelp(t, build, []string{"greet"}, snapshot.Update(*update))
+}
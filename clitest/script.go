@@ -0,0 +1,399 @@
+package clitest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"go.followtheprocess.codes/cli"
+	"go.followtheprocess.codes/cli/internal/shell"
+	"go.followtheprocess.codes/cli/internal/txtar"
+)
+
+// Scripts runs every script matching dir/*.txtar as a subtest named after the file,
+// see [Script] for the format of a script.
+//
+//	func TestScripts(t *testing.T) {
+//		clitest.Scripts(t, "testdata/scripts", map[string]cli.Builder{
+//			"mytool": BuildRoot,
+//		})
+//	}
+func Scripts(t *testing.T, dir string, commands map[string]cli.Builder) {
+	t.Helper()
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*.txtar"))
+	if err != nil {
+		t.Fatalf("clitest: bad script directory %q: %v", dir, err)
+	}
+
+	if len(paths) == 0 {
+		t.Fatalf("clitest: no scripts found in %s", dir)
+	}
+
+	for _, path := range paths {
+		name := strings.TrimSuffix(filepath.Base(path), ".txtar")
+		t.Run(name, func(t *testing.T) {
+			Script(t, path, commands)
+		})
+	}
+}
+
+// Script runs the test script in the txtar archive at path.
+//
+// Each script runs in its own temporary directory (available to the script as $WORK)
+// into which any files in the archive are written first. The comment section of the
+// archive is the script itself, one command per line, with arguments split using
+// shell-like quoting rules, and $VAR or ${VAR} expanded from the script's environment:
+//
+//	# Comments and blank lines are ignored
+//	env MYTOOL_TOKEN=secret
+//	exec mytool serve --port 80
+//	stdout 'listening on :80'
+//	! stderr .
+//
+//	! exec mytool serve --port nope
+//	status 1
+//	stderr 'invalid value'
+//
+//	-- config.toml --
+//	port = 80
+//
+// The commands available are:
+//
+//   - exec name [args...]: run the command built by commands[name] with args, the
+//     command must succeed unless the line is prefixed with '!', in which case it must fail.
+//   - stdout pattern, stderr pattern: the output of the last exec must match the
+//     regular expression pattern, or must not match it if prefixed with '!'. Patterns
+//     are matched in multi-line mode, so ^ and $ match at the start and end of lines.
+//   - status code: the last exec must have exited with code, see [Result].
+//   - cmp file1 file2: the two files must be identical, either file may be 'stdout' or
+//     'stderr' to compare the output of the last exec.
+//   - exists file...: the files must exist, or must not exist if prefixed with '!'.
+//   - env KEY=VALUE...: set environment variables for the rest of the script.
+//   - stdin file: use the contents of file as stdin for the next exec.
+//
+// Commands are run in-process using [RunWith] so no binary is built, and environment
+// variables read by the command's flags are isolated from the test process unless
+// set by env.
+//
+// The script stops at the first failure, and the test is failed with the location
+// of the failing line and a log of what had run up until then.
+func Script(t *testing.T, path string, commands map[string]cli.Builder) {
+	t.Helper()
+
+	archive, err := txtar.ParseFile(path)
+	if err != nil {
+		t.Fatalf("clitest: %v", err)
+	}
+
+	work := t.TempDir()
+
+	if err := archive.Extract(work); err != nil {
+		t.Fatalf("clitest: %s: %v", path, err)
+	}
+
+	t.Chdir(work)
+
+	s := &script{
+		t:        t,
+		commands: commands,
+		env:      map[string]string{"WORK": work},
+		envOrder: []string{"WORK"},
+	}
+
+	for i, line := range strings.Split(archive.Comment, "\n") {
+		if err := s.run(line); err != nil {
+			t.Fatalf("%s:%d: %v\n\n%s", path, i+1, err, s.log.String())
+		}
+	}
+}
+
+// script holds the state of a single running test script.
+type script struct {
+	t        *testing.T
+	commands map[string]cli.Builder // The commands available to exec, by name
+	env      map[string]string      // Environment variables set by the script
+	stdin    []byte                 // Stdin for the next exec, nil if not set
+	log      bytes.Buffer           // Log of the commands run so far, shown on failure
+	envOrder []string               // Keys of env in the order they were first set
+	last     *Result                // Result of the last exec, nil if nothing has run yet
+}
+
+// run executes a single line of the script.
+func (s *script) run(line string) error {
+	words, err := shell.Split(os.Expand(line, s.getenv))
+	if err != nil {
+		return err
+	}
+
+	if len(words) == 0 {
+		return nil
+	}
+
+	fmt.Fprintf(&s.log, "> %s\n", strings.TrimSpace(line))
+
+	negate := words[0] == "!"
+	if negate {
+		words = words[1:]
+		if len(words) == 0 {
+			return errors.New("'!' must be followed by a command")
+		}
+	}
+
+	name, args := words[0], words[1:]
+
+	switch name {
+	case "exec":
+		return s.exec(negate, args)
+	case "stdout", "stderr":
+		return s.match(negate, name, args)
+	case "status":
+		return s.status(negate, args)
+	case "cmp":
+		return s.cmp(negate, args)
+	case "exists":
+		return s.exists(negate, args)
+	case "env":
+		return s.setenv(negate, args)
+	case "stdin":
+		return s.setStdin(negate, args)
+	default:
+		return fmt.Errorf("unknown script command %q", name)
+	}
+}
+
+// exec implements the exec command.
+func (s *script) exec(negate bool, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: exec name [args...]")
+	}
+
+	builder, ok := s.commands[args[0]]
+	if !ok {
+		return fmt.Errorf("unknown command %q, commands must be registered with Script", args[0])
+	}
+
+	options := make([]Option, 0, len(s.envOrder)+1)
+	for _, key := range s.envOrder {
+		options = append(options, Env(key, s.env[key]))
+	}
+
+	if s.stdin != nil {
+		options = append(options, Stdin(bytes.NewReader(s.stdin)))
+		s.stdin = nil
+	}
+
+	result := RunWith(s.t, builder, args[1:], options...)
+	s.last = &result
+
+	if result.Stdout != "" {
+		fmt.Fprintf(&s.log, "[stdout]\n%s", result.Stdout)
+	}
+
+	if result.Stderr != "" {
+		fmt.Fprintf(&s.log, "[stderr]\n%s", result.Stderr)
+	}
+
+	switch {
+	case negate && result.Err == nil:
+		return errors.New("command succeeded unexpectedly")
+	case !negate && result.Err != nil:
+		return fmt.Errorf("command failed unexpectedly: %w", result.Err)
+	case result.Err != nil:
+		fmt.Fprintf(&s.log, "[error] %v\n", result.Err)
+	}
+
+	return nil
+}
+
+// match implements the stdout and stderr commands.
+func (s *script) match(negate bool, stream string, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: %s pattern", stream)
+	}
+
+	output, err := s.output(stream)
+	if err != nil {
+		return err
+	}
+
+	re, err := regexp.Compile("(?m)" + args[0])
+	if err != nil {
+		return fmt.Errorf("bad pattern: %w", err)
+	}
+
+	matched := re.MatchString(output)
+
+	switch {
+	case negate && matched:
+		return fmt.Errorf("unexpected match for %q in %s", args[0], stream)
+	case !negate && !matched:
+		return fmt.Errorf("no match for %q in %s", args[0], stream)
+	}
+
+	return nil
+}
+
+// status implements the status command.
+func (s *script) status(negate bool, args []string) error {
+	if negate {
+		return errors.New("status does not support '!'")
+	}
+
+	if len(args) != 1 {
+		return errors.New("usage: status code")
+	}
+
+	want, err := strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("bad exit code %q: %w", args[0], err)
+	}
+
+	if s.last == nil {
+		return errors.New("status used before any exec")
+	}
+
+	if s.last.ExitCode != want {
+		return fmt.Errorf("got exit code %d, wanted %d", s.last.ExitCode, want)
+	}
+
+	return nil
+}
+
+// cmp implements the cmp command.
+func (s *script) cmp(negate bool, args []string) error {
+	if negate {
+		return errors.New("cmp does not support '!'")
+	}
+
+	if len(args) != 2 { //nolint:mnd // Two files to compare
+		return errors.New("usage: cmp file1 file2")
+	}
+
+	got, err := s.contents(args[0])
+	if err != nil {
+		return err
+	}
+
+	want, err := s.contents(args[1])
+	if err != nil {
+		return err
+	}
+
+	if got != want {
+		return fmt.Errorf("%s and %s differ\n\n--- %s ---\n%s--- %s ---\n%s", args[0], args[1], args[0], got, args[1], want)
+	}
+
+	return nil
+}
+
+// exists implements the exists command.
+func (s *script) exists(negate bool, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: exists file...")
+	}
+
+	for _, file := range args {
+		_, err := os.Stat(file)
+
+		switch {
+		case negate && err == nil:
+			return fmt.Errorf("%s exists but should not", file)
+		case !negate && err != nil:
+			return fmt.Errorf("%s does not exist", file)
+		}
+	}
+
+	return nil
+}
+
+// setenv implements the env command.
+func (s *script) setenv(negate bool, args []string) error {
+	if negate {
+		return errors.New("env does not support '!'")
+	}
+
+	if len(args) == 0 {
+		return errors.New("usage: env KEY=VALUE...")
+	}
+
+	for _, arg := range args {
+		key, value, ok := strings.Cut(arg, "=")
+		if !ok || key == "" {
+			return fmt.Errorf("bad env %q, must be KEY=VALUE", arg)
+		}
+
+		if !slices.Contains(s.envOrder, key) {
+			s.envOrder = append(s.envOrder, key)
+		}
+
+		s.env[key] = value
+	}
+
+	return nil
+}
+
+// setStdin implements the stdin command.
+func (s *script) setStdin(negate bool, args []string) error {
+	if negate {
+		return errors.New("stdin does not support '!'")
+	}
+
+	if len(args) != 1 {
+		return errors.New("usage: stdin file")
+	}
+
+	contents, err := os.ReadFile(args[0])
+	if err != nil {
+		return fmt.Errorf("could not read stdin file: %w", err)
+	}
+
+	s.stdin = contents
+
+	return nil
+}
+
+// output returns the named output stream of the last exec.
+func (s *script) output(stream string) (string, error) {
+	if s.last == nil {
+		return "", fmt.Errorf("%s used before any exec", stream)
+	}
+
+	if stream == "stdout" {
+		return s.last.Stdout, nil
+	}
+
+	return s.last.Stderr, nil
+}
+
+// contents returns the contents of file, which may be 'stdout' or 'stderr' to
+// mean the output of the last exec.
+func (s *script) contents(file string) (string, error) {
+	if file == "stdout" || file == "stderr" {
+		return s.output(file)
+	}
+
+	contents, err := os.ReadFile(file)
+	if err != nil {
+		return "", fmt.Errorf("could not read %s: %w", file, err)
+	}
+
+	return string(contents), nil
+}
+
+// getenv looks up key in the script's environment, falling back to that of
+// the test process.
+func (s *script) getenv(key string) string {
+	if value, ok := s.env[key]; ok {
+		return value
+	}
+
+	return os.Getenv(key)
+}
@@ -0,0 +1,14 @@
+package clitest_test
+
+import (
+	"testing"
+
+	"go.followtheprocess.codes/cli"
+	"go.followtheprocess.codes/cli/clitest"
+)
+
+func TestScripts(t *testing.T) {
+	clitest.Scripts(t, "testdata/scripts", map[string]cli.Builder{
+		"greeter": build,
+	})
+}
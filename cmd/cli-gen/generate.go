@@ -0,0 +1,789 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"go/format"
+	"io/fs"
+	"net"
+	"net/netip"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"go.followtheprocess.codes/cli/flag"
+	"go.followtheprocess.codes/cli/internal/parse"
+	"go.followtheprocess.codes/cli/spec"
+)
+
+// Import paths the generated code may need.
+const (
+	importCLI     = "go.followtheprocess.codes/cli"
+	importFlag    = "go.followtheprocess.codes/cli/flag"
+	importContext = "context"
+	importErrors  = "errors"
+	importNet     = "net"
+	importNetip   = "net/netip"
+	importURL     = "net/url"
+	importOS      = "os"
+	importRegexp  = "regexp"
+	importTime    = "time"
+)
+
+// goType is how a spec type is written in Go, and what it needs importing.
+type goType struct {
+	name    string // The Go type e.g. "time.Duration"
+	imp     string // Import path needed for the type, if any
+	argable bool   // Whether the type may be used for a positional argument
+}
+
+// goTypes maps the types used in a spec (the same ones shown in help text) to their Go types.
+//
+//nolint:gochecknoglobals // Effectively a constant
+var goTypes = map[string]goType{
+	"int":        {name: "int", argable: true},
+	"int8":       {name: "int8", argable: true},
+	"int16":      {name: "int16", argable: true},
+	"int32":      {name: "int32", argable: true},
+	"int64":      {name: "int64", argable: true},
+	"uint":       {name: "uint", argable: true},
+	"uint8":      {name: "uint8", argable: true},
+	"uint16":     {name: "uint16", argable: true},
+	"uint32":     {name: "uint32", argable: true},
+	"uint64":     {name: "uint64", argable: true},
+	"uintptr":    {name: "uintptr", argable: true},
+	"float32":    {name: "float32", argable: true},
+	"float64":    {name: "float64", argable: true},
+	"string":     {name: "string", argable: true},
+	"bool":       {name: "bool", argable: true},
+	"count":      {name: "flag.Count", imp: importFlag},
+	"bytesHex":   {name: "[]byte", argable: true},
+	"time":       {name: "time.Time", imp: importTime, argable: true},
+	"duration":   {name: "time.Duration", imp: importTime, argable: true},
+	"ip":         {name: "net.IP", imp: importNet, argable: true},
+	"url":        {name: "*url.URL", imp: importURL, argable: true},
+	"addr":       {name: "netip.Addr", imp: importNetip},
+	"prefix":     {name: "netip.Prefix", imp: importNetip},
+	"addrPort":   {name: "netip.AddrPort", imp: importNetip},
+	"regexp":     {name: "*regexp.Regexp", imp: importRegexp},
+	"fileMode":   {name: "os.FileMode", imp: importOS},
+	"byteSize":   {name: "flag.ByteSize", imp: importFlag},
+	"[]int":      {name: "[]int"},
+	"[]int8":     {name: "[]int8"},
+	"[]int16":    {name: "[]int16"},
+	"[]int32":    {name: "[]int32"},
+	"[]int64":    {name: "[]int64"},
+	"[]uint":     {name: "[]uint"},
+	"[]uint16":   {name: "[]uint16"},
+	"[]uint32":   {name: "[]uint32"},
+	"[]uint64":   {name: "[]uint64"},
+	"[]float32":  {name: "[]float32"},
+	"[]float64":  {name: "[]float64"},
+	"[]string":   {name: "[]string"},
+	"[]bool":     {name: "[]bool"},
+	"[]duration": {name: "[]time.Duration", imp: importTime},
+	"[]ip":       {name: "[]net.IP", imp: importNet},
+	"[]url":      {name: "[]*url.URL", imp: importURL},
+}
+
+// generator builds the Go source for a command tree.
+type generator struct {
+	imports  map[string]bool // Import paths used so far
+	body     bytes.Buffer    // Everything after the imports
+	commands []command       // Every command in the tree, parents before children
+}
+
+// command is a command from the spec, along with where it sits in the tree.
+type command struct {
+	spec  spec.Command
+	path  []string // Names from the root down to and including this command
+	ident string   // Go identifier for the command e.g. "MytoolServe"
+}
+
+// runnable reports whether the command gets a run function, only leaf commands do.
+func (c command) runnable() bool {
+	return len(c.spec.Commands) == 0
+}
+
+// hasOptions reports whether the command needs an options struct, either because it
+// has flags or arguments to store, or because its run function is passed one.
+func (c command) hasOptions() bool {
+	return c.runnable() || len(c.spec.Flags) != 0 || len(c.spec.Args) != 0
+}
+
+// generate returns the formatted Go source, in package pkg, for the command tree
+// described by root. The source is the name of the spec file, mentioned in the
+// generated code's header.
+func generate(root spec.Command, pkg, source string) ([]byte, error) {
+	g := &generator{imports: map[string]bool{importCLI: true}}
+
+	if err := g.collect(root, nil); err != nil {
+		return nil, err
+	}
+
+	g.writeHandlers()
+
+	for _, cmd := range g.commands {
+		g.writeOptions(cmd)
+
+		if err := g.writeBuilder(cmd); err != nil {
+			return nil, err
+		}
+	}
+
+	out := &bytes.Buffer{}
+	fmt.Fprintf(out, "// Code generated by cli-gen from %s. DO NOT EDIT.\n\n", source)
+	fmt.Fprintf(out, "package %s\n\n", pkg)
+	out.WriteString("import (\n")
+
+	// Standard library first, then everything else, as goimports would
+	var std, other []string
+
+	for imp := range g.imports {
+		if first, _, _ := strings.Cut(imp, "/"); strings.Contains(first, ".") {
+			other = append(other, imp)
+		} else {
+			std = append(std, imp)
+		}
+	}
+
+	slices.Sort(std)
+	slices.Sort(other)
+
+	for _, imp := range std {
+		fmt.Fprintf(out, "\t%q\n", imp)
+	}
+
+	out.WriteString("\n")
+
+	for _, imp := range other {
+		fmt.Fprintf(out, "\t%q\n", imp)
+	}
+
+	out.WriteString(")\n\n")
+	out.Write(g.body.Bytes())
+
+	formatted, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("generated code is invalid: %w", err)
+	}
+
+	return formatted, nil
+}
+
+// collect validates cmd and its subcommands, adding them to the generator's list.
+func (g *generator) collect(cmd spec.Command, parent []string) error {
+	if cmd.Name == "" {
+		return fmt.Errorf("command under %q has no name", strings.Join(parent, " "))
+	}
+
+	path := append(slices.Clip(parent), cmd.Name)
+	where := strings.Join(path, " ")
+
+	// Flags and arguments become fields of the same struct so their names must not clash
+	fields := make(map[string]string)
+
+	for _, f := range cmd.Flags {
+		if err := checkField(fields, f.Name, "flag --"+f.Name); err != nil {
+			return fmt.Errorf("%s: %w", where, err)
+		}
+
+		if _, ok := goTypes[f.Type]; !ok {
+			return fmt.Errorf("%s: flag --%s has unknown type %q", where, f.Name, f.Type)
+		}
+
+		if len([]rune(f.Short)) > 1 {
+			return fmt.Errorf("%s: flag --%s has a shorthand %q, shorthands must be a single character", where, f.Name, f.Short)
+		}
+	}
+
+	for _, a := range cmd.Args {
+		if err := checkField(fields, a.Name, "argument "+a.Name); err != nil {
+			return fmt.Errorf("%s: %w", where, err)
+		}
+
+		if typ, ok := goTypes[a.Type]; !ok || !typ.argable {
+			return fmt.Errorf("%s: argument %s has unsupported type %q", where, a.Name, a.Type)
+		}
+	}
+
+	g.commands = append(g.commands, command{spec: cmd, path: path, ident: identifier(strings.Join(path, "-"))})
+
+	names := make([]string, 0, len(cmd.Commands))
+	for _, sub := range cmd.Commands {
+		if slices.Contains(names, sub.Name) {
+			return fmt.Errorf("%s: subcommand %q defined more than once", where, sub.Name)
+		}
+
+		names = append(names, sub.Name)
+
+		if err := g.collect(sub, path); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkField records the Go field name for a flag or argument called name, returning
+// an error if another flag or argument already has it.
+func checkField(fields map[string]string, name, what string) error {
+	if name == "" {
+		return errors.New("flags and arguments must have a name")
+	}
+
+	field := identifier(name)
+	if other, exists := fields[field]; exists {
+		return fmt.Errorf("%s and %s would both be the field %s", other, what, field)
+	}
+
+	fields[field] = what
+
+	return nil
+}
+
+// writeHandlers writes the Handlers struct, with a run function for each runnable command.
+func (g *generator) writeHandlers() {
+	g.imports[importContext] = true
+
+	root := g.commands[0]
+
+	fmt.Fprintf(&g.body, "// Handlers are the run functions for the commands in the %s command tree.\n", root.spec.Name)
+	g.body.WriteString("type Handlers struct {\n")
+
+	for _, cmd := range g.commands {
+		if !cmd.runnable() {
+			continue
+		}
+
+		fmt.Fprintf(&g.body, "\t// %s runs '%s'.\n", cmd.ident, strings.Join(cmd.path, " "))
+		fmt.Fprintf(&g.body, "\t%s func(ctx context.Context, cmd *cli.Command, opts *%sOptions) error\n", cmd.ident, cmd.ident)
+	}
+
+	g.body.WriteString("}\n\n")
+}
+
+// writeOptions writes the struct holding the values of cmd's flags and arguments.
+func (g *generator) writeOptions(cmd command) {
+	if !cmd.hasOptions() {
+		return
+	}
+
+	fmt.Fprintf(&g.body, "// %sOptions holds the flags and arguments of '%s'.\n", cmd.ident, strings.Join(cmd.path, " "))
+	fmt.Fprintf(&g.body, "type %sOptions struct {\n", cmd.ident)
+
+	for _, f := range cmd.spec.Flags {
+		typ := g.use(f.Type)
+		fmt.Fprintf(&g.body, "\t// %s is the value of the --%s flag.\n", identifier(f.Name), f.Name)
+		fmt.Fprintf(&g.body, "\t%s %s\n", identifier(f.Name), typ.name)
+	}
+
+	for _, a := range cmd.spec.Args {
+		typ := g.use(a.Type)
+		fmt.Fprintf(&g.body, "\t// %s is the value of the %s argument.\n", identifier(a.Name), a.Name)
+		fmt.Fprintf(&g.body, "\t%s %s\n", identifier(a.Name), typ.name)
+	}
+
+	g.body.WriteString("}\n\n")
+}
+
+// writeBuilder writes the function returning the [cli.Builder] for cmd.
+func (g *generator) writeBuilder(cmd command) error {
+	s := cmd.spec
+	path := strings.Join(cmd.path, " ")
+
+	if len(cmd.path) == 1 {
+		fmt.Fprintf(&g.body, "// Build%s returns the builder for the %s command tree, running each command with\n", cmd.ident, s.Name)
+		g.body.WriteString("// its function in handlers.\n")
+		fmt.Fprintf(&g.body, "func Build%s(handlers Handlers) cli.Builder {\n", cmd.ident)
+	} else {
+		fmt.Fprintf(&g.body, "// build%s returns the builder for '%s'.\n", cmd.ident, path)
+		fmt.Fprintf(&g.body, "func build%s(handlers Handlers) cli.Builder {\n", cmd.ident)
+	}
+
+	g.body.WriteString("return func() (*cli.Command, error) {\n")
+
+	if cmd.runnable() {
+		g.imports[importErrors] = true
+		fmt.Fprintf(&g.body, "if handlers.%s == nil {\n", cmd.ident)
+		fmt.Fprintf(&g.body, "return nil, errors.New(%q)\n", "no handler for '"+path+"'")
+		g.body.WriteString("}\n\n")
+	}
+
+	if cmd.hasOptions() {
+		fmt.Fprintf(&g.body, "var opts %sOptions\n\n", cmd.ident)
+	}
+
+	g.body.WriteString("return cli.New(\n")
+	fmt.Fprintf(&g.body, "%q,\n", s.Name)
+
+	if s.Short != "" {
+		fmt.Fprintf(&g.body, "cli.Short(%q),\n", s.Short)
+	}
+
+	if s.Long != "" {
+		fmt.Fprintf(&g.body, "cli.Long(%q),\n", s.Long)
+	}
+
+	if s.Group != "" {
+		fmt.Fprintf(&g.body, "cli.Group(%q),\n", s.Group)
+	}
+
+	for _, example := range s.Examples {
+		fmt.Fprintf(&g.body, "cli.Example(%q, %q),\n", example.Comment, example.Command)
+	}
+
+	for _, f := range s.Flags {
+		if err := g.writeFlag(f); err != nil {
+			return fmt.Errorf("%s: flag --%s: %w", path, f.Name, err)
+		}
+	}
+
+	for _, a := range s.Args {
+		if err := g.writeArg(a); err != nil {
+			return fmt.Errorf("%s: argument %s: %w", path, a.Name, err)
+		}
+	}
+
+	if len(s.Commands) != 0 {
+		builders := make([]string, 0, len(s.Commands))
+		for _, sub := range s.Commands {
+			builders = append(builders, "build"+identifier(strings.Join(append(slices.Clip(cmd.path), sub.Name), "-"))+"(handlers)")
+		}
+
+		fmt.Fprintf(&g.body, "cli.SubCommands(%s),\n", strings.Join(builders, ", "))
+	}
+
+	if cmd.runnable() {
+		g.body.WriteString("cli.Run(func(ctx context.Context, cmd *cli.Command) error {\n")
+		fmt.Fprintf(&g.body, "return handlers.%s(ctx, cmd, &opts)\n", cmd.ident)
+		g.body.WriteString("}),\n")
+	}
+
+	g.body.WriteString(")\n}\n}\n\n")
+
+	return nil
+}
+
+// writeFlag writes the cli.Flag option for f.
+func (g *generator) writeFlag(f spec.Flag) error {
+	typ := g.use(f.Type)
+
+	short := "flag.NoShortHand"
+	if f.Short != "" {
+		short = strconv.QuoteRune([]rune(f.Short)[0])
+	} else {
+		g.imports[importFlag] = true
+	}
+
+	var options []string
+
+	for _, alias := range f.Aliases {
+		options = append(options, fmt.Sprintf("cli.FlagAlias[%s](%q)", typ.name, alias))
+	}
+
+	if f.Default != "" {
+		value, err := g.literal(f.Type, f.Default)
+		if err != nil {
+			return fmt.Errorf("bad default: %w", err)
+		}
+
+		options = append(options, fmt.Sprintf("cli.FlagDefault[%s](%s)", typ.name, value))
+	}
+
+	if f.NoArgDefault != "" {
+		value, err := g.literal(f.Type, f.NoArgDefault)
+		if err != nil {
+			return fmt.Errorf("bad no-arg default: %w", err)
+		}
+
+		options = append(options, fmt.Sprintf("cli.NoArgDefault[%s](%s)", typ.name, value))
+	}
+
+	if f.Delimiter != "" {
+		options = append(options, fmt.Sprintf("cli.Delimiter[%s](%q)", typ.name, f.Delimiter))
+	}
+
+	if f.ReplaceDefault {
+		options = append(options, fmt.Sprintf("cli.ReplaceDefault[%s]()", typ.name))
+	}
+
+	if f.Env != "" {
+		options = append(options, fmt.Sprintf("cli.Env[%s](%q)", typ.name, f.Env))
+	}
+
+	if f.Group != "" {
+		options = append(options, fmt.Sprintf("cli.FlagGroup[%s](%q)", typ.name, f.Group))
+	}
+
+	if f.Required {
+		options = append(options, fmt.Sprintf("cli.Required[%s]()", typ.name))
+	}
+
+	if f.Sensitive {
+		options = append(options, fmt.Sprintf("cli.Sensitive[%s]()", typ.name))
+	}
+
+	if len(f.Choices) != 0 {
+		choices, err := g.literals(f.Type, f.Choices)
+		if err != nil {
+			return fmt.Errorf("bad choice: %w", err)
+		}
+
+		options = append(options, fmt.Sprintf("cli.Choices[%s](%s)", typ.name, strings.Join(choices, ", ")))
+	}
+
+	if f.Deprecated != "" {
+		options = append(options, fmt.Sprintf("cli.DeprecatedFlag[%s](%q)", typ.name, f.Deprecated))
+	}
+
+	if f.ReplacedBy != "" {
+		options = append(options, fmt.Sprintf("cli.ReplacedBy[%s](%q)", typ.name, f.ReplacedBy))
+	}
+
+	if f.Hidden {
+		options = append(options, fmt.Sprintf("cli.HiddenFlag[%s]()", typ.name))
+	}
+
+	fmt.Fprintf(&g.body, "cli.Flag(&opts.%s, %q, %s, %q", identifier(f.Name), f.Name, short, f.Usage)
+
+	for _, option := range options {
+		g.body.WriteString(", ")
+		g.body.WriteString(option)
+	}
+
+	g.body.WriteString("),\n")
+
+	return nil
+}
+
+// writeArg writes the cli.Arg option for a.
+func (g *generator) writeArg(a spec.Arg) error {
+	typ := g.use(a.Type)
+
+	var options []string
+
+	if a.Default != "" {
+		value, err := g.literal(a.Type, a.Default)
+		if err != nil {
+			return fmt.Errorf("bad default: %w", err)
+		}
+
+		options = append(options, fmt.Sprintf("cli.ArgDefault[%s](%s)", typ.name, value))
+	}
+
+	if len(a.Choices) != 0 {
+		choices, err := g.literals(a.Type, a.Choices)
+		if err != nil {
+			return fmt.Errorf("bad choice: %w", err)
+		}
+
+		options = append(options, fmt.Sprintf("cli.ArgChoices[%s](%s)", typ.name, strings.Join(choices, ", ")))
+	}
+
+	fmt.Fprintf(&g.body, "cli.Arg(&opts.%s, %q, %q", identifier(a.Name), a.Name, a.Usage)
+
+	for _, option := range options {
+		g.body.WriteString(", ")
+		g.body.WriteString(option)
+	}
+
+	g.body.WriteString("),\n")
+
+	return nil
+}
+
+// use returns the goType for the spec type typ, recording any import it needs. The
+// type must have been validated already.
+func (g *generator) use(typ string) goType {
+	t := goTypes[typ]
+	if t.imp != "" {
+		g.imports[t.imp] = true
+	}
+
+	return t
+}
+
+// literals returns the Go literals for each of values, of the spec type typ.
+func (g *generator) literals(typ string, values []string) ([]string, error) {
+	literals := make([]string, 0, len(values))
+
+	for _, value := range values {
+		literal, err := g.literal(typ, value)
+		if err != nil {
+			return nil, err
+		}
+
+		literals = append(literals, literal)
+	}
+
+	return literals, nil
+}
+
+// literal returns the Go literal for value, of the spec type typ. Slice values
+// are comma separated, with the same quoting as a flag with a "," delimiter.
+func (g *generator) literal(typ, value string) (string, error) {
+	if elem, ok := strings.CutPrefix(typ, "[]"); ok {
+		values, err := parse.Split(value, ",")
+		if err != nil {
+			return "", err
+		}
+
+		items, err := g.literals(elem, values)
+		if err != nil {
+			return "", err
+		}
+
+		return g.use(typ).name + "{" + strings.Join(items, ", ") + "}", nil
+	}
+
+	var (
+		literal string
+		err     error
+	)
+
+	switch typ {
+	case "int", "int8", "int16", "int32", "int64":
+		var n int64
+		n, err = strconv.ParseInt(value, 0, bitSize(typ))
+		literal = strconv.FormatInt(n, 10)
+	case "uint", "uint8", "uint16", "uint32", "uint64", "uintptr", "count":
+		var n uint64
+		n, err = strconv.ParseUint(value, 0, bitSize(typ))
+		literal = strconv.FormatUint(n, 10)
+	case "float32", "float64":
+		var f float64
+		f, err = strconv.ParseFloat(value, bitSize(typ))
+		literal = strconv.FormatFloat(f, 'g', -1, bitSize(typ))
+	case "bool":
+		var b bool
+		b, err = strconv.ParseBool(value)
+		literal = strconv.FormatBool(b)
+	case "string":
+		literal = strconv.Quote(value)
+	case "bytesHex":
+		var b []byte
+		b, err = hex.DecodeString(strings.TrimSpace(value))
+		literal = bytesLiteral(b)
+	case "time":
+		var t time.Time
+		t, err = parse.Time(value)
+		literal = timeLiteral(t)
+		g.imports[importTime] = true
+	case "duration":
+		var d time.Duration
+		d, err = time.ParseDuration(value)
+		literal = durationLiteral(d)
+		g.imports[importTime] = true
+	case "ip":
+		ip := net.ParseIP(value)
+		if ip == nil {
+			err = errors.New("not an IP address")
+		}
+
+		literal = fmt.Sprintf("net.ParseIP(%q)", ip.String())
+		g.imports[importNet] = true
+	case "url":
+		var u *url.URL
+		u, err = url.ParseRequestURI(value)
+		if err == nil {
+			literal = urlLiteral(u)
+		}
+
+		g.imports[importURL] = true
+	case "addr":
+		_, err = netip.ParseAddr(value)
+		literal = fmt.Sprintf("netip.MustParseAddr(%q)", value)
+		g.imports[importNetip] = true
+	case "prefix":
+		_, err = netip.ParsePrefix(value)
+		literal = fmt.Sprintf("netip.MustParsePrefix(%q)", value)
+		g.imports[importNetip] = true
+	case "addrPort":
+		_, err = netip.ParseAddrPort(value)
+		literal = fmt.Sprintf("netip.MustParseAddrPort(%q)", value)
+		g.imports[importNetip] = true
+	case "regexp":
+		_, err = regexp.Compile(value)
+		literal = fmt.Sprintf("regexp.MustCompile(%q)", value)
+		g.imports[importRegexp] = true
+	case "fileMode":
+		var mode fs.FileMode
+		mode, err = parse.FileMode(value)
+		literal = "0o" + strconv.FormatUint(uint64(mode), 8)
+	case "byteSize":
+		var size flag.ByteSize
+		size, err = parse.ByteSize(value)
+		literal = byteSizeLiteral(size)
+		g.imports[importFlag] = true
+	default:
+		return "", fmt.Errorf("values for %s flags or arguments are not supported", typ)
+	}
+
+	if err != nil {
+		var numErr *strconv.NumError
+		if errors.As(err, &numErr) {
+			err = numErr.Err
+		}
+
+		return "", fmt.Errorf("invalid %s %q: %w", typ, value, err)
+	}
+
+	return literal, nil
+}
+
+// bitSize returns the size in bits of a numeric spec type, 0 meaning the platform size.
+func bitSize(typ string) int {
+	switch typ {
+	case "int8", "uint8":
+		return 8 //nolint:mnd // Bit sizes are what they are
+	case "int16", "uint16":
+		return 16 //nolint:mnd // Bit sizes are what they are
+	case "int32", "uint32", "float32":
+		return 32 //nolint:mnd // Bit sizes are what they are
+	case "int64", "uint64", "float64":
+		return 64 //nolint:mnd // Bit sizes are what they are
+	default:
+		return 0
+	}
+}
+
+// byteSizeLiteral returns the most readable Go expression for size e.g. "10 * flag.MiB".
+func byteSizeLiteral(size flag.ByteSize) string {
+	// String already picks the largest exact unit e.g. "10MiB"
+	str := size.String()
+	number := strings.TrimRightFunc(str, unicode.IsLetter)
+
+	unit := str[len(number):]
+	if unit == "B" {
+		return number
+	}
+
+	return number + " * flag." + unit
+}
+
+// bytesLiteral returns the Go expression for b e.g. "[]byte{0xde, 0xad}".
+func bytesLiteral(b []byte) string {
+	items := make([]string, 0, len(b))
+	for _, c := range b {
+		items = append(items, fmt.Sprintf("%#02x", c))
+	}
+
+	return "[]byte{" + strings.Join(items, ", ") + "}"
+}
+
+// timeLiteral returns the Go expression for t e.g.
+// "time.Date(2024, time.July, 17, 7, 38, 5, 0, time.UTC)".
+func timeLiteral(t time.Time) string {
+	location := "time.UTC"
+	if _, offset := t.Zone(); offset != 0 {
+		location = fmt.Sprintf("time.FixedZone(\"\", %d)", offset)
+	}
+
+	return fmt.Sprintf(
+		"time.Date(%d, time.%s, %d, %d, %d, %d, %d, %s)",
+		t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), location,
+	)
+}
+
+// urlLiteral returns the Go expression for u, setting only the fields it uses
+// e.g. `&url.URL{Scheme: "https", Host: "example.com"}`.
+func urlLiteral(u *url.URL) string {
+	var fields []string
+
+	str := func(name, value string) {
+		if value != "" {
+			fields = append(fields, fmt.Sprintf("%s: %q", name, value))
+		}
+	}
+
+	str("Scheme", u.Scheme)
+	str("Opaque", u.Opaque)
+
+	if u.User != nil {
+		if password, ok := u.User.Password(); ok {
+			fields = append(fields, fmt.Sprintf("User: url.UserPassword(%q, %q)", u.User.Username(), password))
+		} else {
+			fields = append(fields, fmt.Sprintf("User: url.User(%q)", u.User.Username()))
+		}
+	}
+
+	str("Host", u.Host)
+	str("Path", u.Path)
+	str("RawPath", u.RawPath)
+
+	if u.OmitHost {
+		fields = append(fields, "OmitHost: true")
+	}
+
+	if u.ForceQuery {
+		fields = append(fields, "ForceQuery: true")
+	}
+
+	str("RawQuery", u.RawQuery)
+	str("Fragment", u.Fragment)
+	str("RawFragment", u.RawFragment)
+
+	return "&url.URL{" + strings.Join(fields, ", ") + "}"
+}
+
+// durationLiteral returns the most readable Go expression for d e.g. "90 * time.Second".
+func durationLiteral(d time.Duration) string {
+	units := []struct {
+		name string
+		size time.Duration
+	}{
+		{"time.Hour", time.Hour},
+		{"time.Minute", time.Minute},
+		{"time.Second", time.Second},
+		{"time.Millisecond", time.Millisecond},
+		{"time.Microsecond", time.Microsecond},
+	}
+
+	if d == 0 {
+		return "0"
+	}
+
+	for _, unit := range units {
+		if d%unit.size == 0 {
+			return fmt.Sprintf("%d * %s", d/unit.size, unit.name)
+		}
+	}
+
+	return strconv.FormatInt(int64(d), 10)
+}
+
+// identifier converts a command, flag or argument name to an exported Go identifier,
+// e.g. "dry-run" to "DryRun".
+func identifier(name string) string {
+	s := &strings.Builder{}
+
+	upper := true
+
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+
+		if s.Len() == 0 && unicode.IsDigit(r) {
+			// Identifiers can't start with a digit
+			s.WriteByte('X')
+		}
+
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+
+		s.WriteRune(r)
+	}
+
+	return s.String()
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	goflag "flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.followtheprocess.codes/cli/spec"
+	"go.followtheprocess.codes/snapshot"
+	"go.followtheprocess.codes/test"
+	"go.yaml.in/yaml/v4"
+)
+
+var update = goflag.Bool("update", false, "Update golden files")
+
+func TestGenerate(t *testing.T) {
+	tests := []string{"mytool.yaml"}
+
+	for _, file := range tests {
+		t.Run(file, func(t *testing.T) {
+			snap := snapshot.New(
+				t,
+				snapshot.Update(*update),
+				snapshot.WithFormatter(snapshot.TextFormatter()),
+			)
+
+			contents, err := os.ReadFile(filepath.Join("testdata", "specs", file))
+			test.Ok(t, err)
+
+			var root spec.Command
+			test.Ok(t, yaml.Unmarshal(contents, &root))
+
+			code, err := generate(root, "main", file)
+			test.Ok(t, err)
+
+			snap.Snap(string(code))
+		})
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	tests := []struct {
+		name   string       // Name of the test case
+		errMsg string       // Expected error message
+		root   spec.Command // Spec to generate from
+	}{
+		{
+			name:   "unknown flag type",
+			root:   spec.Command{Name: "test", Flags: []spec.Flag{{Name: "thing", Type: "widget"}}},
+			errMsg: `test: flag --thing has unknown type "widget"`,
+		},
+		{
+			name:   "count argument",
+			root:   spec.Command{Name: "test", Args: []spec.Arg{{Name: "n", Type: "count"}}},
+			errMsg: `test: argument n has unsupported type "count"`,
+		},
+		{
+			name:   "long shorthand",
+			root:   spec.Command{Name: "test", Flags: []spec.Flag{{Name: "force", Short: "fo", Type: "bool"}}},
+			errMsg: `test: flag --force has a shorthand "fo", shorthands must be a single character`,
+		},
+		{
+			name: "field collision",
+			root: spec.Command{
+				Name:  "test",
+				Flags: []spec.Flag{{Name: "dry-run", Type: "bool"}},
+				Args:  []spec.Arg{{Name: "dry_run", Type: "string"}},
+			},
+			errMsg: "test: flag --dry-run and argument dry_run would both be the field DryRun",
+		},
+		{
+			name: "duplicate subcommand",
+			root: spec.Command{
+				Name:     "test",
+				Commands: []spec.Command{{Name: "sub"}, {Name: "sub"}},
+			},
+			errMsg: `test: subcommand "sub" defined more than once`,
+		},
+		{
+			name:   "bad default",
+			root:   spec.Command{Name: "test", Flags: []spec.Flag{{Name: "count", Type: "int", Default: "lots"}}},
+			errMsg: `test: flag --count: bad default: invalid int "lots": invalid syntax`,
+		},
+		{
+			name:   "bad ip default",
+			root:   spec.Command{Name: "test", Flags: []spec.Flag{{Name: "addr", Type: "ip", Default: "localhost"}}},
+			errMsg: `test: flag --addr: bad default: invalid ip "localhost": not an IP address`,
+		},
+		{
+			name:   "bad slice default",
+			root:   spec.Command{Name: "test", Flags: []spec.Flag{{Name: "tag", Type: "[]string", Default: `"a,b`}}},
+			errMsg: `test: flag --tag: bad default: unterminated quote in "\"a,b"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := generate(tt.root, "main", "test.yaml")
+			test.Err(t, err)
+			test.Equal(t, err.Error(), tt.errMsg)
+		})
+	}
+}
@@ -0,0 +1,268 @@
+// Command cli-gen generates the code for a cli command tree from a declarative spec, and
+// describes the command tree of an existing program as a spec.
+//
+// Generate Go code for the command tree in a YAML (or JSON) spec:
+//
+//	cli-gen generate --package main --output cli_gen.go mytool.yaml
+//
+// Describe the command tree of a program built with cli, e.g. to review or diff
+// changes to its interface:
+//
+//	cli-gen describe ./bin/mytool > mytool.yaml
+//
+// Check for breaking changes between two versions of an interface, each either a spec
+// or a program:
+//
+//	cli-gen compat mytool.yaml ./bin/mytool
+package main
+
+import (
+	"bytes"
+	"context"
+	"debug/buildinfo"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+
+	"go.followtheprocess.codes/cli"
+	"go.followtheprocess.codes/cli/compat"
+	"go.followtheprocess.codes/cli/spec"
+	"go.yaml.in/yaml/v4"
+)
+
+// cliModule is the module path of cli, a program must depend on it to be described.
+const cliModule = "go.followtheprocess.codes/cli"
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	cmd, err := cli.New(
+		"cli-gen",
+		cli.Short("Generate, describe and check cli command trees"),
+		cli.Example("Generate code from a spec", "cli-gen generate --output cli_gen.go mytool.yaml"),
+		cli.Example("Describe an existing program", "cli-gen describe ./bin/mytool > mytool.yaml"),
+		cli.Example("Check for breaking changes", "cli-gen compat mytool.yaml ./bin/mytool"),
+		cli.SubCommands(buildGenerate, buildDescribe, buildCompat),
+	)
+	if err != nil {
+		return fmt.Errorf("could not build root command: %w", err)
+	}
+
+	return cmd.Execute(context.Background())
+}
+
+type generateOptions struct {
+	pkg    string
+	output string
+	spec   string
+}
+
+func buildGenerate() (*cli.Command, error) {
+	var options generateOptions
+
+	return cli.New(
+		"generate",
+		cli.Short("Generate Go code from a YAML or JSON spec"),
+		cli.Flag(&options.pkg, "package", 'p', "Package of the generated code", cli.FlagDefault("main")),
+		cli.Flag(&options.output, "output", 'o', "File to write the code to, stdout if not set"),
+		cli.Arg(&options.spec, "spec", "Path to the spec file"),
+		cli.Run(func(ctx context.Context, cmd *cli.Command) error {
+			root, err := compat.Load(options.spec)
+			if err != nil {
+				return err
+			}
+
+			code, err := generate(root, options.pkg, filepath.Base(options.spec))
+			if err != nil {
+				return fmt.Errorf("could not generate code from %s: %w", options.spec, err)
+			}
+
+			if options.output == "" {
+				_, err = cmd.Stdout().Write(code)
+				return err
+			}
+
+			return os.WriteFile(options.output, code, 0o644) //nolint:gosec // Generated source is not secret
+		}),
+	)
+}
+
+type describeOptions struct {
+	format string
+}
+
+func buildDescribe() (*cli.Command, error) {
+	var options describeOptions
+
+	return cli.New(
+		"describe",
+		cli.Short("Print the spec of a program built with cli"),
+		cli.Long(
+			"The program must be a Go binary built with cli, it is run with an environment variable set "+
+				"that makes it print its spec rather than doing anything. Any arguments after the program "+
+				"are passed to it, so the spec is of the command tree it builds for those arguments.",
+		),
+		cli.Example("Describe a program as YAML", "cli-gen describe ./bin/mytool"),
+		cli.Flag(
+			&options.format,
+			"format",
+			'f',
+			"Format of the spec",
+			cli.FlagDefault("yaml"),
+			cli.Choices("yaml", "json"),
+		),
+		cli.Run(func(ctx context.Context, cmd *cli.Command) error {
+			args := cmd.Args()
+			if len(args) == 0 {
+				return errors.New("describe requires the path to a program, got none")
+			}
+
+			root, err := describe(ctx, cmd, args[0], args[1:]...)
+			if err != nil {
+				return err
+			}
+
+			return writeSpec(cmd, root, options.format)
+		}),
+	)
+}
+
+type compatOptions struct {
+	old string
+	new string
+}
+
+func buildCompat() (*cli.Command, error) {
+	var options compatOptions
+
+	return cli.New(
+		"compat",
+		cli.Short("Report breaking changes between two versions of an interface"),
+		cli.Long(
+			"Each version is either a YAML or JSON spec, or a program built with cli which is described "+
+				"as in 'cli-gen describe'. Every breaking change is printed and the command fails if there are any.",
+		),
+		cli.Example("Compare a checked in spec with a new build", "cli-gen compat mytool.yaml ./bin/mytool"),
+		cli.Arg(&options.old, "old", "The previous spec or program"),
+		cli.Arg(&options.new, "new", "The updated spec or program"),
+		cli.Run(func(ctx context.Context, cmd *cli.Command) error {
+			old, err := load(ctx, cmd, options.old)
+			if err != nil {
+				return err
+			}
+
+			updated, err := load(ctx, cmd, options.new)
+			if err != nil {
+				return err
+			}
+
+			changes := compat.Check(old, updated)
+			for _, change := range changes {
+				fmt.Fprintln(cmd.Stdout(), change)
+			}
+
+			if len(changes) != 0 {
+				return fmt.Errorf("found %d breaking change(s) from %s to %s", len(changes), options.old, options.new)
+			}
+
+			return nil
+		}),
+	)
+}
+
+// load returns the spec at path, which is either a spec file or a program to describe.
+func load(ctx context.Context, cmd *cli.Command, path string) (spec.Command, error) {
+	switch filepath.Ext(path) {
+	case ".yaml", ".yml", ".json":
+		return compat.Load(path)
+	default:
+		return describe(ctx, cmd, path)
+	}
+}
+
+// describe runs program with args and the [cli.DescribeEnv] environment variable set,
+// returning the spec it prints.
+//
+// Programs that aren't built with cli are never run, as they wouldn't know to print
+// their spec rather than doing whatever it is they do.
+func describe(ctx context.Context, cmd *cli.Command, program string, args ...string) (spec.Command, error) {
+	path, err := builtWithCLI(program)
+	if err != nil {
+		return spec.Command{}, err
+	}
+
+	stdout := &bytes.Buffer{}
+	run := exec.CommandContext(ctx, path, args...) //nolint:gosec // Running the program is the point
+	run.Env = append(slices.Clone(os.Environ()), cli.DescribeEnv)
+	run.Stdout = stdout
+	run.Stderr = cmd.Stderr()
+
+	if err := run.Run(); err != nil {
+		return spec.Command{}, fmt.Errorf("could not describe %s: %w", program, err)
+	}
+
+	var root spec.Command
+	if err := json.Unmarshal(stdout.Bytes(), &root); err != nil {
+		return spec.Command{}, fmt.Errorf("%s did not print a spec, is it built with cli?: %w", program, err)
+	}
+
+	return root, nil
+}
+
+// builtWithCLI returns the path to program, or an error if it is not a Go binary that
+// depends on cli. The build info is read from the binary without running it.
+func builtWithCLI(program string) (string, error) {
+	path, err := exec.LookPath(program)
+	if err != nil {
+		return "", fmt.Errorf("could not find %s: %w", program, err)
+	}
+
+	info, err := buildinfo.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("%s is not a Go program built with cli: %w", program, err)
+	}
+
+	if info.Main.Path == cliModule {
+		return path, nil
+	}
+
+	for _, dep := range info.Deps {
+		if dep.Path == cliModule {
+			return path, nil
+		}
+	}
+
+	return "", fmt.Errorf("%s is not built with cli, it does not depend on %s", program, cliModule)
+}
+
+// writeSpec writes root to cmd's stdout in the given format.
+func writeSpec(cmd *cli.Command, root spec.Command, format string) error {
+	if format == "json" {
+		out, err := json.MarshalIndent(root, "", "  ")
+		if err != nil {
+			return fmt.Errorf("could not encode spec: %w", err)
+		}
+
+		fmt.Fprintln(cmd.Stdout(), string(out))
+
+		return nil
+	}
+
+	encoder := yaml.NewEncoder(cmd.Stdout())
+	encoder.SetIndent(2) //nolint:mnd // Two spaces is the conventional YAML indent
+
+	if err := encoder.Encode(root); err != nil {
+		return fmt.Errorf("could not encode spec: %w", err)
+	}
+
+	return encoder.Close()
+}
@@ -17,6 +17,8 @@ import (
 
 	"go.followtheprocess.codes/cli/internal/arg"
 	"go.followtheprocess.codes/cli/internal/flag"
+	"go.followtheprocess.codes/cli/internal/fromfile"
+	"go.followtheprocess.codes/cli/internal/pager"
 	"go.followtheprocess.codes/cli/internal/style"
 )
 
@@ -41,6 +43,7 @@ var (
 	commandsTitle  = style.Title.Text("Commands")
 	argumentsTitle = style.Title.Text("Arguments")
 	examplesTitle  = style.Title.Text("Examples")
+	topicsTitle    = style.Title.Text("Additional Help Topics")
 )
 
 // Builder is a function that constructs and returns a [Command], it makes constructing
@@ -95,6 +98,34 @@ func New(name string, options ...Option) (*Command, error) {
 		return nil, errs
 	}
 
+	if err := cmd.flags.CheckReplacements(); err != nil {
+		return nil, err
+	}
+
+	// Commands that prompt must always have a way to turn it off e.g. for scripts
+	if _, exists := cmd.flags.Get(noInputFlag); !exists && hasPrompts(cmd) {
+		err := addAutoBoolFlag(cmd.flags, &cmd.noInput, noInputFlag, publicflag.NoShortHand, "Disable interactive prompts")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Plugins add their own subcommand, which must be there before the check below so a
+	// command made up of only plugins is valid
+	if cmd.pluginPrefix != "" {
+		if findSubCommand(cmd, pluginsCommandName) != nil {
+			return nil, fmt.Errorf("command %s: cannot use the Plugins option with a subcommand named %q", cmd.name, pluginsCommandName)
+		}
+
+		plugins, err := newPluginsCommand()
+		if err != nil {
+			return nil, err
+		}
+
+		plugins.builtin = true
+		cmd.subcommands = append(cmd.subcommands, plugins)
+	}
+
 	// Additional validation that can't be done per-option
 	// A command cannot have no subcommands and no run function, it must define one or the other
 	if cmd.run == nil && len(cmd.subcommands) == 0 {
@@ -104,6 +135,34 @@ func New(name string, options ...Option) (*Command, error) {
 		)
 	}
 
+	// The shell subcommand must exist before the help subcommand is considered, so a command
+	// with only a run function still gets 'help' to go with it
+	if cmd.shell {
+		if findSubCommand(cmd, shellCommandName) != nil {
+			return nil, fmt.Errorf("command %s: cannot use the Shell option with a subcommand named %q", cmd.name, shellCommandName)
+		}
+
+		shell, err := newShellCommand()
+		if err != nil {
+			return nil, err
+		}
+
+		shell.builtin = true
+		cmd.subcommands = append(cmd.subcommands, shell)
+	}
+
+	// Any command with subcommands or help topics gets a "help" subcommand so users
+	// can type e.g. 'mytool help serve start' (unless the user has defined their own)
+	if (len(cmd.subcommands) != 0 || len(cmd.topics) != 0) && findSubCommand(cmd, helpCommandName) == nil {
+		help, err := newHelpCommand()
+		if err != nil {
+			return nil, err
+		}
+
+		help.builtin = true
+		cmd.subcommands = append(cmd.subcommands, help)
+	}
+
 	// Loop through each subcommand and set this command as their immediate parent
 	for _, subcommand := range cmd.subcommands {
 		subcommand.parent = cmd
@@ -168,6 +227,10 @@ type Command struct {
 	// short is the one line summary for the command, shown inline in the -h/--help output.
 	short string
 
+	// group is the title of the help section this command is listed under in its
+	// parent's -h/--help output, commands without a group are listed under "Commands".
+	group string
+
 	// long is the long form description for the command, shown when -h/--help is called on the command itself.
 	long string
 
@@ -183,6 +246,10 @@ type Command struct {
 	// examples is examples of how to use the command.
 	examples []example
 
+	// topics are additional, non-runnable help pages accessible through
+	// the built-in help subcommand e.g. 'mytool help environment'.
+	topics []helpTopic
+
 	// rawArgs are the raw arguments passed to the command prior to any parsing, defaulting to [os.Args]
 	// (excluding the command name, so os.Args[1:]), can be overridden using
 	// the [OverrideArgs] option for e.g. testing.
@@ -203,6 +270,39 @@ type Command struct {
 
 	// versionCalled is whether or not the --version flag was used.
 	versionCalled bool
+
+	// noInput is whether or not the --no-input flag was used, only added
+	// to commands with flags or arguments that prompt for their value.
+	noInput bool
+
+	// pager is whether long help text should be shown through the user's pager
+	// when writing to a terminal, set with the [Pager] option.
+	pager bool
+
+	// interactive is whether stdin is treated as interactive even if it's not a
+	// terminal, set with the [Interactive] option.
+	interactive bool
+
+	// responseFiles is whether "@file" arguments are expanded to the contents of
+	// the file before parsing, set with the [ResponseFiles] option.
+	responseFiles bool
+
+	// shell is whether the command has a "shell" subcommand, set with
+	// the [Shell] option.
+	shell bool
+
+	// pluginPrefix is the prefix of the external executables that extend the command,
+	// set with the [Plugins] option. Empty if plugins are disabled.
+	pluginPrefix string
+
+	// lazy builds the real command in place of this one, which is only a placeholder
+	// holding its name and short description, see [Lazy]. Nil for commands that have
+	// been built.
+	lazy Builder
+
+	// builtin is whether the command was added automatically e.g. the help subcommand,
+	// rather than defined by the user.
+	builtin bool
 }
 
 // example is a single usage example for a [Command].
@@ -216,6 +316,17 @@ type example struct {
 	command string // The command string for the example.
 }
 
+// helpTopic is a non-runnable page of help text for a concept rather than a
+// command, e.g. the config file format.
+//
+// Help topics are listed in the -h/--help output and shown with
+// 'mytool help <topic>'.
+type helpTopic struct {
+	name  string // The name of the topic, as typed after 'help'.
+	short string // One line summary shown in the topic list.
+	body  string // The full text of the topic.
+}
+
 // Execute parses the flags and arguments, and invokes the Command's Run
 // function, returning any error.
 //
@@ -233,11 +344,43 @@ func (cmd *Command) Execute(ctx context.Context) error {
 		return fmt.Errorf("Execute must be called on the root of the command tree, was called on %s", cmd.name)
 	}
 
+	describe, err := describeRequested()
+	if err != nil {
+		return err
+	}
+
+	if describe {
+		return writeDescription(cmd)
+	}
+
+	return cmd.execute(ctx, cmd.rawArgs)
+}
+
+// execute implements [Command.Execute] for the root command cmd, using rawArgs in
+// place of the command's own raw arguments e.g. for each line typed into the shell.
+func (cmd *Command) execute(ctx context.Context, rawArgs []string) error {
+	if cmd.responseFiles {
+		expanded, err := expandResponseFiles(rawArgs)
+		if err != nil {
+			return err
+		}
+
+		rawArgs = expanded
+	}
+
 	// Use the raw arguments and the command tree to determine which subcommand (if any)
 	// we should be invoking and swap that into 'cmd'.
 	//
 	// Slightly magical trick but it simplifies a lot of stuff below.
-	cmd, args := findRequestedCommand(cmd, cmd.rawArgs)
+	cmd, args, err := findRequestedCommand(cmd, rawArgs)
+	if err != nil {
+		return err
+	}
+
+	// If what's left names an external plugin, everything from its name onwards is the plugin's
+	plugin, args, pluginArgs := splitPlugin(cmd, args)
+
+	cmd.flagSet().SetStdin(cmd.Stdin())
 
 	if err := cmd.flagSet().Parse(args); err != nil {
 		return fmt.Errorf("failed to parse command flags: %w", err)
@@ -275,6 +418,22 @@ func (cmd *Command) Execute(ctx context.Context) error {
 		return nil
 	}
 
+	warnDeprecated(cmd)
+
+	if plugin != "" {
+		return runPlugin(ctx, cmd, plugin, pluginArgs)
+	}
+
+	if err := promptFlags(cmd); err != nil {
+		return err
+	}
+
+	for name, fl := range cmd.flagSet().Sorted() {
+		if fl.Required() && !cmd.flagSet().Changed(name) {
+			return &MissingFlagError{Name: name}
+		}
+	}
+
 	nonExtraArgs := cmd.flagSet().Args()
 	terminatorIndex := slices.Index(nonExtraArgs, "--")
 
@@ -287,11 +446,32 @@ func (cmd *Command) Execute(ctx context.Context) error {
 		// The argument has been provided
 		if len(nonExtraArgs) > i {
 			str = nonExtraArgs[i]
+
+			if argument.FromFile() {
+				values, err := fromfile.Values(str, cmd.Stdin(), false)
+				if err != nil {
+					return fmt.Errorf("argument %q: %w", argument.Name(), err)
+				}
+
+				str = values[0]
+			}
 		} else {
-			// It hasn't, use the default
+			// It hasn't, ask for it if we can
+			if argument.Prompt() {
+				answered, err := askForArg(cmd, argument)
+				if err != nil {
+					return err
+				}
+
+				if answered {
+					continue
+				}
+			}
+
+			// Otherwise use the default
 			str = argument.Default()
 			if str == "" {
-				return fmt.Errorf("argument %q is required and no value was provided", argument.Name())
+				return &MissingArgumentError{Name: argument.Name()}
 			}
 		}
 
@@ -305,8 +485,13 @@ func (cmd *Command) Execute(ctx context.Context) error {
 		return cmd.run(ctx, cmd)
 	}
 
-	// The only way we get here is if the command has subcommands defined but got no arguments given to it
-	// so just show the usage and error
+	// The only way we get here is if the command has subcommands defined but no run function, so
+	// either the user has asked for a subcommand that doesn't exist or didn't ask for one at all
+	if len(nonExtraArgs) != 0 {
+		return unknownCommand(cmd, nonExtraArgs[0], false)
+	}
+
+	// No arguments given, just show the usage and error
 	if err := showHelp(cmd); err != nil {
 		return err
 	}
@@ -314,6 +499,16 @@ func (cmd *Command) Execute(ctx context.Context) error {
 	return fmt.Errorf("command %q expected arguments (subcommands) but got none", cmd.name)
 }
 
+// warnDeprecated writes a warning to cmd's stderr for every deprecated flag that was
+// used, on the command line or by its environment variable.
+func warnDeprecated(cmd *Command) {
+	for name, fl := range cmd.flagSet().Sorted() {
+		if fl.Deprecated() != "" && cmd.flagSet().Changed(name) {
+			fmt.Fprintf(cmd.Stderr(), "Warning: flag --%s is deprecated, %s\n", name, fl.Deprecated())
+		}
+	}
+}
+
 // Stdout returns the configured Stdout for the Command.
 func (cmd *Command) Stdout() io.Writer {
 	return cmd.root().stdout
@@ -329,6 +524,45 @@ func (cmd *Command) Stdin() io.Reader {
 	return cmd.root().stdin
 }
 
+// Pager returns an [io.WriteCloser] that shows everything written to it through the
+// user's terminal pager (e.g. less) for output that may be too long to fit on screen.
+//
+// Paging only happens if it was enabled on the root command with the [Pager] option
+// and stdout is a terminal. Otherwise, or if the pager cannot be started, writes go
+// directly to [Command.Stdout] so callers need not care whether paging is in effect.
+//
+// The returned writer must be closed once all output has been written, this waits
+// for the user to exit the pager.
+//
+//	w := cmd.Pager()
+//	defer w.Close()
+//	fmt.Fprintln(w, lotsOfText)
+func (cmd *Command) Pager() io.WriteCloser {
+	root := cmd.root()
+
+	if root.pager {
+		if _, ok := pager.Height(root.stdout); ok {
+			if argv, ok := pager.Command(pager.EnvVar(root.name)); ok {
+				if w, err := pager.Start(argv, root.stdout); err == nil {
+					return w
+				}
+			}
+		}
+	}
+
+	return nopCloser{root.stdout}
+}
+
+// nopCloser wraps an [io.Writer] with a no-op Close method.
+type nopCloser struct {
+	io.Writer
+}
+
+// Close implements [io.Closer] and does nothing.
+func (nopCloser) Close() error {
+	return nil
+}
+
 // Args returns the positional arguments passed to the command.
 func (cmd *Command) Args() []string {
 	return cmd.flagSet().Args()
@@ -366,30 +600,41 @@ func (cmd *Command) root() *Command {
 	return cmd
 }
 
-// hasFlag returns whether the command has a flag of the given name defined.
-func (cmd *Command) hasFlag(name string) bool {
-	flag, ok := cmd.flagSet().Get(name)
-	if !ok {
-		return false
+// takesValue reports whether a flag given without an "=", looked up with ok reporting
+// whether the command has it, takes the first of the remaining arguments as its value.
+//
+// Unknown flags are assumed to take a value. Flags with an implied value (e.g. bool) don't,
+// unless they accept the next argument as a value and it isn't the name of a subcommand
+// e.g. "--verbose 3" for a count flag.
+func (cmd *Command) takesValue(fl flag.Value, ok bool, rest []string) bool {
+	if !ok || fl.NoArgValue() == "" {
+		return true
 	}
 
-	return flag.NoArgValue() != ""
+	return len(rest) > 0 && fl.TakesNext(rest[0]) && findSubCommand(cmd, rest[0]) == nil
 }
 
-// hasShortFlag returns whether the command has a shorthand flag of the given name defined.
-func (cmd *Command) hasShortFlag(name string) bool {
-	if name == "" {
-		return false
-	}
-
-	char, _ := utf8.DecodeRuneInString(name)
+// shortTakesValue reports whether a group of shorthand flags e.g. "vv", takes the first
+// of the remaining arguments as the value of the last flag in the group.
+//
+// A flag that needs a value anywhere before the end of the group takes the rest of
+// the group instead e.g. "-p8080", so the next argument is left alone.
+func (cmd *Command) shortTakesValue(shorthands string, rest []string) bool {
+	for shorthands != "" {
+		char, size := utf8.DecodeRuneInString(shorthands)
+		shorthands = shorthands[size:]
+
+		fl, ok := cmd.flagSet().GetShort(char)
+		if shorthands == "" {
+			return cmd.takesValue(fl, ok, rest)
+		}
 
-	flag, ok := cmd.flagSet().GetShort(char)
-	if !ok {
-		return false
+		if !ok || fl.NoArgValue() == "" {
+			return false
+		}
 	}
 
-	return flag.NoArgValue() != ""
+	return false
 }
 
 // findRequestedCommand uses the raw arguments and the command tree to determine what
@@ -400,7 +645,10 @@ func (cmd *Command) hasShortFlag(name string) bool {
 // slice that we own; subsequent levels then mutate that slice in place via
 // [slices.Delete]. The original cmd.rawArgs is never touched, so re-Execute
 // on the same Command still sees pristine input.
-func findRequestedCommand(cmd *Command, args []string) (*Command, []string) {
+//
+// Any lazy subcommands (see [Lazy]) along the way are built as they are descended
+// into, an error is only returned if one of them fails to build.
+func findRequestedCommand(cmd *Command, args []string) (*Command, []string, error) {
 	owned := false
 
 	for {
@@ -408,12 +656,18 @@ func findRequestedCommand(cmd *Command, args []string) (*Command, []string) {
 		// e.g. in 'go mod tidy' we're looking for 'mod'.
 		idx, ok := firstNonFlagArg(cmd, args)
 		if !ok {
-			return cmd, args
+			return cmd, args, nil
 		}
 
 		next := findSubCommand(cmd, args[idx])
 		if next == nil {
-			return cmd, args
+			return cmd, args, nil
+		}
+
+		// We need its flags to find the next subcommand, so it must be built now
+		next, err := next.resolve()
+		if err != nil {
+			return nil, nil, err
 		}
 
 		if !owned {
@@ -453,16 +707,27 @@ func firstNonFlagArg(cmd *Command, args []string) (idx int, ok bool) {
 		case a == "--":
 			// "--" terminates the flags
 			return -1, false
-		case strings.HasPrefix(a, "--") && !strings.Contains(a, "=") && !cmd.hasFlag(a[2:]):
-			// If '--flag value' then skip value
-			fallthrough
-		case strings.HasPrefix(a, "-") && !strings.Contains(a, "=") && len(a) == 2 && !cmd.hasShortFlag(a[1:]):
-			// '-f value' skip the value too. If there isn't one, we're done.
-			if i+1 >= len(args) {
-				return -1, false
+		case strings.HasPrefix(a, "--") && !strings.Contains(a, "="):
+			// If '--flag value' then skip value. If there isn't one, we're done.
+			fl, ok := cmd.flagSet().Get(a[2:])
+			if cmd.takesValue(fl, ok, args[i+1:]) {
+				if i+1 >= len(args) {
+					return -1, false
+				}
+
+				i++
 			}
 
-			i++
+			continue
+		case strings.HasPrefix(a, "-") && !strings.Contains(a, "=") && len(a) > 1:
+			// '-f value' or '-vv 3' skip the value too
+			if cmd.shortTakesValue(a[1:], args[i+1:]) {
+				if i+1 >= len(args) {
+					return -1, false
+				}
+
+				i++
+			}
 
 			continue
 		case a != "" && !strings.HasPrefix(a, "-"):
@@ -563,16 +828,54 @@ func showHelp(cmd *Command) error {
 		return err
 	}
 
+	// External plugins found on $PATH
+	if err := writePlugins(cmd, s, tw); err != nil {
+		return err
+	}
+
+	// Any additional help topics
+	if len(cmd.topics) != 0 {
+		if err := writeTopics(cmd, s, tw); err != nil {
+			return err
+		}
+	}
+
 	// Subcommand help
 	if len(cmd.subcommands) != 0 {
 		writeFooter(cmd, s)
 	}
 
+	writeHelp(cmd, s.String())
+
+	return nil
+}
+
+// writeHelp writes the rendered help text to the command's stderr.
+//
+// If the root command has paging enabled and the text is too long to fit in the
+// terminal, it's shown through the user's pager instead. Any failure to start the
+// pager falls back to writing directly.
+func writeHelp(cmd *Command, text string) {
 	// Note: It's important to use cmd.Stderr() here over cmd.stderr
 	// as it resolves to the root's stderr
-	fmt.Fprint(cmd.Stderr(), s.String())
+	stderr := cmd.Stderr()
+
+	if cmd.root().pager {
+		if height, ok := pager.Height(stderr); ok && strings.Count(text, "\n") >= height {
+			if argv, ok := pager.Command(pager.EnvVar(cmd.root().name)); ok {
+				if w, err := pager.Start(argv, stderr); err == nil {
+					// Errors here are almost always the user quitting the pager
+					// before reading everything, which is fine
+					io.WriteString(w, text) //nolint:errcheck // See above
+					w.Close()               //nolint:errcheck // See above
+
+					return
+				}
+			}
+		}
+	}
 
-	return nil
+	fmt.Fprint(stderr, text)
 }
 
 // writePositionalArgs writes any positional arguments in the correct
@@ -651,6 +954,9 @@ func writeExamples(cmd *Command, s *strings.Builder) {
 }
 
 // writeSubcommands writes the subcommand block to the help text string builder.
+//
+// Subcommands without a group are listed first under the default "Commands" section,
+// followed by a titled section for each group in the order they were declared.
 func writeSubcommands(cmd *Command, s *strings.Builder, tw *tabwriter.Writer) error {
 	// If there were examples, the last one would have printed a newline
 	if len(cmd.examples) != 0 {
@@ -659,13 +965,48 @@ func writeSubcommands(cmd *Command, s *strings.Builder, tw *tabwriter.Writer) er
 		s.WriteString("\n\n")
 	}
 
-	s.WriteString(commandsTitle)
+	groups := subcommandGroups(cmd)
+
+	// Only omit the default section if every subcommand is in a group, builtins like
+	// help don't count as they can't be put in one
+	ungrouped := func(sub *Command) bool { return sub.group == "" && !sub.builtin }
+	if len(groups) == 0 || slices.ContainsFunc(cmd.subcommands, ungrouped) {
+		if err := writeSubcommandSection(cmd, s, tw, commandsTitle, ""); err != nil {
+			return err
+		}
+
+		if len(groups) != 0 {
+			s.WriteByte('\n')
+		}
+	}
+
+	for i, group := range groups {
+		if i != 0 {
+			s.WriteByte('\n')
+		}
+
+		if err := writeSubcommandSection(cmd, s, tw, style.Title.Text(group), group); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeSubcommandSection writes a single titled section of subcommands, listing only
+// those whose group matches the one given.
+func writeSubcommandSection(cmd *Command, s *strings.Builder, tw *tabwriter.Writer, title, group string) error {
+	s.WriteString(title)
 	s.WriteByte(':')
 	s.WriteString("\n\n")
 
 	style.ResetTabwriter(tw, s)
 
 	for _, subcommand := range cmd.subcommands {
+		if subcommand.group != group {
+			continue
+		}
+
 		fmt.Fprintf(tw, "  %s\t%s\n", style.Bold.Text(subcommand.name), subcommand.short)
 	}
 
@@ -676,11 +1017,51 @@ func writeSubcommands(cmd *Command, s *strings.Builder, tw *tabwriter.Writer) er
 	return nil
 }
 
+// subcommandGroups returns the titles of the groups used by cmd's immediate
+// subcommands, in the order they first appear.
+func subcommandGroups(cmd *Command) []string {
+	var groups []string
+
+	for _, subcommand := range cmd.subcommands {
+		if subcommand.group != "" && !slices.Contains(groups, subcommand.group) {
+			groups = append(groups, subcommand.group)
+		}
+	}
+
+	return groups
+}
+
 // writeFlags writes the flag usage block to the help text string builder.
+//
+// Flags without a group are written first (directly under the "Options" title written
+// by the caller) followed by a titled section for each flag group in declaration order.
 func writeFlags(cmd *Command, s *strings.Builder, tw *tabwriter.Writer) error {
+	if err := writeFlagSection(cmd, s, tw, ""); err != nil {
+		return err
+	}
+
+	for _, group := range cmd.flags.Groups() {
+		s.WriteByte('\n')
+		s.WriteString(style.Title.Text(group))
+		s.WriteString(":\n\n")
+
+		if err := writeFlagSection(cmd, s, tw, group); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeFlagSection writes the usage for all flags belonging to group, in alphabetical order.
+func writeFlagSection(cmd *Command, s *strings.Builder, tw *tabwriter.Writer, group string) error {
 	style.ResetTabwriter(tw, s)
 
 	for name, fl := range cmd.flags.Sorted() {
+		if fl.Group() != group || fl.Hidden() {
+			continue
+		}
+
 		var shorthand string
 		if fl.Short() != publicflag.NoShortHand {
 			shorthand = "-" + string(fl.Short())
@@ -689,7 +1070,9 @@ func writeFlags(cmd *Command, s *strings.Builder, tw *tabwriter.Writer) error {
 		}
 
 		defaultStr := ""
-		if fl.Default() != "" {
+		if fl.Required() {
+			defaultStr = "[required]"
+		} else if fl.Default() != "" {
 			defaultStr = "[default: " + fl.Default() + "]"
 		}
 
@@ -698,10 +1081,21 @@ func writeFlags(cmd *Command, s *strings.Builder, tw *tabwriter.Writer) error {
 			envStr = "(env: $" + fl.EnvVar() + ")"
 		}
 
-		fmt.Fprintf(tw, "  %s\t--%s\t%s\t%s\t%s\t%s\n",
+		names := "--" + name
+		for _, alias := range fl.Aliases() {
+			names += ", --" + alias
+		}
+
+		// Flags that may be given bare show the value that implies e.g. string[=always]
+		typ := fl.Type()
+		if fl.NoArgDefault() != "" {
+			typ += "[=" + fl.NoArgDefault() + "]"
+		}
+
+		fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\t%s\t%s\n",
 			style.Bold.Text(shorthand),
-			style.Bold.Text(name),
-			fl.Type(),
+			style.Bold.Text(names),
+			typ,
 			fl.Usage(),
 			defaultStr,
 			envStr,
@@ -715,6 +1109,25 @@ func writeFlags(cmd *Command, s *strings.Builder, tw *tabwriter.Writer) error {
 	return nil
 }
 
+// writeTopics writes the additional help topics block to the help text string builder.
+func writeTopics(cmd *Command, s *strings.Builder, tw *tabwriter.Writer) error {
+	s.WriteByte('\n')
+	s.WriteString(topicsTitle)
+	s.WriteString(":\n\n")
+
+	style.ResetTabwriter(tw, s)
+
+	for _, topic := range cmd.topics {
+		fmt.Fprintf(tw, "  %s\t%s\n", style.Bold.Text(topic.name), topic.short)
+	}
+
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("could not format help topics: %w", err)
+	}
+
+	return nil
+}
+
 // writeFooter writes the footer to the help text string builder.
 func writeFooter(cmd *Command, s *strings.Builder) {
 	s.WriteByte('\n')
@@ -724,6 +1137,28 @@ func writeFooter(cmd *Command, s *strings.Builder) {
 	s.WriteString(`" `)
 	s.WriteString("for more information about a command.")
 	s.WriteByte('\n')
+
+	if len(cmd.topics) != 0 {
+		s.WriteString(`Use "`)
+		s.WriteString(cmd.name)
+		s.WriteString(" help [topic]")
+		s.WriteString(`" `)
+		s.WriteString("for more information about a topic.")
+		s.WriteByte('\n')
+	}
+}
+
+// showTopic writes the full text of a help topic to the command's stderr.
+func showTopic(cmd *Command, topic helpTopic) {
+	s := &strings.Builder{}
+	s.Grow(len(topic.short) + len(topic.body) + len("\n\n\n"))
+
+	s.WriteString(topic.short)
+	s.WriteString("\n\n")
+	s.WriteString(topic.body)
+	s.WriteByte('\n')
+
+	writeHelp(cmd, s.String())
 }
 
 // showVersion is the default implementation of the --version flag.
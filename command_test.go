@@ -1,18 +1,31 @@
 package cli_test
 
 import (
+	"bufio"
 	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	goflag "flag"
 	"fmt"
 	"io"
+	"io/fs"
 	"math/rand/v2"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"runtime"
 	"slices"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"go.followtheprocess.codes/cli"
 	"go.followtheprocess.codes/cli/flag"
+	"go.followtheprocess.codes/cli/spec"
 	"go.followtheprocess.codes/snapshot"
 	"go.followtheprocess.codes/test"
 )
@@ -385,6 +398,33 @@ func TestHelp(t *testing.T) {
 		)
 	}
 
+	managed := func() (*cli.Command, error) {
+		return cli.New(
+			"network",
+			cli.Short("Manage networks"),
+			cli.Group("Management Commands"),
+			cli.Run(func(ctx context.Context, cmd *cli.Command) error { return nil }),
+		)
+	}
+
+	volume := func() (*cli.Command, error) {
+		return cli.New(
+			"volume",
+			cli.Short("Manage volumes"),
+			cli.Group("Management Commands"),
+			cli.Run(func(ctx context.Context, cmd *cli.Command) error { return nil }),
+		)
+	}
+
+	plugin := func() (*cli.Command, error) {
+		return cli.New(
+			"plugin",
+			cli.Short("Manage plugins"),
+			cli.Group("Extending"),
+			cli.Run(func(ctx context.Context, cmd *cli.Command) error { return nil }),
+		)
+	}
+
 	tests := []struct {
 		name    string       // Identifier of the test case
 		options []cli.Option // Options to apply to the command
@@ -524,6 +564,84 @@ func TestHelp(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "with grouped subcommands",
+			options: []cli.Option{
+				cli.OverrideArgs([]string{"--help"}),
+				cli.Short("A cool CLI to do things"),
+				cli.SubCommands(managed, sub1, plugin, volume, sub2),
+			},
+			wantErr: false,
+		},
+		{
+			name: "with only grouped subcommands",
+			options: []cli.Option{
+				cli.OverrideArgs([]string{"--help"}),
+				cli.Short("A cool CLI to do things"),
+				cli.SubCommands(plugin, managed, volume),
+			},
+			wantErr: false,
+		},
+		{
+			name: "with flag groups",
+			options: []cli.Option{
+				cli.OverrideArgs([]string{"--help"}),
+				cli.Short("A cool CLI to do things"),
+				cli.Flag(new(bool), "force", 'f', "Force something"),
+				cli.Flag(new(int), "port", 'p', "Port to listen on", cli.FlagGroup[int]("Networking")),
+				cli.Flag(new(string), "config", 'c', "Path to a config file", cli.FlagGroup[string]("Configuration")),
+				cli.Flag(
+					new(string),
+					"host",
+					flag.NoShortHand,
+					"Host to bind to",
+					cli.FlagDefault("localhost"),
+					cli.FlagGroup[string]("Networking"),
+				),
+				cli.Run(func(ctx context.Context, cmd *cli.Command) error { return nil }),
+			},
+			wantErr: false,
+		},
+		{
+			name: "with sensitive flag",
+			options: []cli.Option{
+				cli.OverrideArgs([]string{"--help"}),
+				cli.Short("A cool CLI to do things"),
+				cli.Flag(
+					new(string),
+					"api-key",
+					flag.NoShortHand,
+					"API key to authenticate with",
+					cli.FlagDefault("super-secret"),
+					cli.Sensitive[string](),
+				),
+				cli.Flag(new(string), "token", 't', "A token with no default", cli.Sensitive[string]()),
+				cli.Run(func(ctx context.Context, cmd *cli.Command) error { return nil }),
+			},
+			wantErr: false,
+		},
+		{
+			name: "with prompts",
+			options: []cli.Option{
+				cli.OverrideArgs([]string{"--help"}),
+				cli.Short("A cool CLI to do things"),
+				cli.Flag(new(string), "env", 'e', "Environment to deploy to", cli.Prompt[string]()),
+				cli.Arg(new(string), "name", "Name of the project", cli.ArgPrompt[string]()),
+				cli.Run(func(ctx context.Context, cmd *cli.Command) error { return nil }),
+			},
+			wantErr: false,
+		},
+		{
+			name: "with help topics",
+			options: []cli.Option{
+				cli.OverrideArgs([]string{"--help"}),
+				cli.Short("A cool CLI to do things"),
+				cli.SubCommands(sub1, sub2),
+				cli.HelpTopic("environment", "Environment variables used by test", "TEST_THING: Sets the thing"),
+				cli.HelpTopic("config-format", "The format of the config file", "It's TOML"),
+			},
+			wantErr: false,
+		},
 		{
 			name: "flag with env var",
 			options: []cli.Option{
@@ -557,6 +675,40 @@ func TestHelp(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "with hidden flags",
+			options: []cli.Option{
+				cli.OverrideArgs([]string{"--help"}),
+				cli.Short("A test command"),
+				cli.Flag(new(string), "listen", 'l', "Address to listen on"),
+				cli.Flag(new(string), "addr", flag.NoShortHand, "Address to listen on", cli.ReplacedBy[string]("listen"), cli.HiddenFlag[string]()),
+				cli.Flag(new(bool), "trace", flag.NoShortHand, "Trace internals", cli.HiddenFlag[bool](), cli.FlagGroup[bool]("Debug")),
+				cli.Run(func(_ context.Context, _ *cli.Command) error { return nil }),
+			},
+			wantErr: false,
+		},
+		{
+			name: "with no-arg defaults",
+			options: []cli.Option{
+				cli.OverrideArgs([]string{"--help"}),
+				cli.Short("A test command"),
+				cli.Flag(new(string), "colour", 'c', "Colour the output", cli.FlagDefault[string]("auto"), cli.NoArgDefault[string]("always")),
+				cli.Flag(new(string), "profile", flag.NoShortHand, "Write a CPU profile", cli.NoArgDefault[string]("cpu.prof")),
+				cli.Run(func(_ context.Context, _ *cli.Command) error { return nil }),
+			},
+			wantErr: false,
+		},
+		{
+			name: "with flag aliases",
+			options: []cli.Option{
+				cli.OverrideArgs([]string{"--help"}),
+				cli.Short("A test command"),
+				cli.Flag(new(string), "colour", 'c', "Output colour", cli.FlagAlias[string]("color")),
+				cli.Flag(new(bool), "dry-run", flag.NoShortHand, "Don't do anything", cli.FlagAlias[bool]("noop"), cli.FlagAlias[bool]("whatif")),
+				cli.Run(func(_ context.Context, _ *cli.Command) error { return nil }),
+			},
+			wantErr: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -596,6 +748,155 @@ func TestHelp(t *testing.T) {
 	}
 }
 
+func TestHelpSubcommand(t *testing.T) {
+	start := func() (*cli.Command, error) {
+		return cli.New(
+			"start",
+			cli.Short("Start the server"),
+			cli.Run(func(ctx context.Context, cmd *cli.Command) error { return nil }),
+		)
+	}
+
+	sub := func() (*cli.Command, error) {
+		return cli.New(
+			"serve",
+			cli.Short("Run the server"),
+			cli.SubCommands(start),
+			cli.HelpTopic("ports", "Ports used by the server", "8080 by default"),
+		)
+	}
+
+	tests := []struct {
+		name    string   // Name of the test case
+		stderr  string   // Expected prefix of stderr
+		errMsg  string   // Expected error message, if wantErr
+		args    []string // Arguments passed to the root command
+		wantErr bool     // Whether we want an error
+	}{
+		{
+			name:    "topic",
+			args:    []string{"help", "environment"},
+			stderr:  "Environment variables used by test\n\nTEST_THING: Sets the thing\n",
+			wantErr: false,
+		},
+		{
+			name:    "command",
+			args:    []string{"help", "serve"},
+			stderr:  "Run the server\n\nUsage: serve [OPTIONS] COMMAND\n\nCommands:\n\n",
+			wantErr: false,
+		},
+		{
+			name:    "nested command",
+			args:    []string{"help", "serve", "start"},
+			stderr:  "Start the server\n\nUsage: start [OPTIONS] ARGS...\n\nOptions:\n\n",
+			wantErr: false,
+		},
+		{
+			name:    "nested topic",
+			args:    []string{"help", "serve", "ports"},
+			stderr:  "Ports used by the server\n\n8080 by default\n",
+			wantErr: false,
+		},
+		{
+			name:    "help on a subcommand",
+			args:    []string{"serve", "help", "start"},
+			stderr:  "Start the server\n\nUsage: start [OPTIONS] ARGS...\n\nOptions:\n\n",
+			wantErr: false,
+		},
+		{
+			name:    "no args shows parent",
+			args:    []string{"help"},
+			stderr:  "A test command\n\nUsage: test [OPTIONS] COMMAND\n\nCommands:\n\n",
+			wantErr: false,
+		},
+		{
+			name:    "unknown",
+			args:    []string{"help", "nope"},
+			wantErr: true,
+			errMsg:  "unknown command or help topic \"nope\" for \"test\"\n\nRun 'test --help' for usage.",
+		},
+		{
+			name:    "unknown with suggestion",
+			args:    []string{"help", "sevre"},
+			wantErr: true,
+			errMsg: "unknown command or help topic \"sevre\" for \"test\"\n\nDid you mean this?\n\tserve" +
+				"\n\nRun 'test --help' for usage.",
+		},
+		{
+			name:    "unknown nested with suggestion",
+			args:    []string{"help", "serve", "stat"},
+			wantErr: true,
+			errMsg: "unknown command or help topic \"stat\" for \"test serve\"\n\nDid you mean this?\n\tstart" +
+				"\n\nRun 'test serve --help' for usage.",
+		},
+		{
+			name:    "too many words",
+			args:    []string{"help", "serve", "start", "now"},
+			wantErr: true,
+			errMsg:  "unknown command or help topic \"now\" for \"test serve start\"\n\nRun 'test serve start --help' for usage.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stderr := &bytes.Buffer{}
+
+			cmd, err := cli.New(
+				"test",
+				cli.Short("A test command"),
+				cli.Stderr(stderr),
+				cli.Stdout(io.Discard),
+				cli.NoColour(true),
+				cli.SubCommands(sub),
+				cli.HelpTopic("environment", "Environment variables used by test", "  TEST_THING: Sets the thing\n\n"),
+				cli.OverrideArgs(tt.args),
+			)
+			test.Ok(t, err)
+
+			err = cmd.Execute(t.Context())
+			test.WantErr(t, err, tt.wantErr)
+
+			if tt.wantErr {
+				test.Equal(t, err.Error(), tt.errMsg)
+
+				return
+			}
+
+			// Full help text is covered by the snapshots in TestHelp
+			test.True(t, strings.HasPrefix(stderr.String(), tt.stderr))
+		})
+	}
+}
+
+func TestUserDefinedHelpSubcommand(t *testing.T) {
+	// If the user defines their own help subcommand, it must be used in
+	// place of the built-in one
+	stdout := &bytes.Buffer{}
+
+	help := func() (*cli.Command, error) {
+		return cli.New(
+			"help",
+			cli.Run(func(ctx context.Context, cmd *cli.Command) error {
+				fmt.Fprintln(cmd.Stdout(), "custom help")
+
+				return nil
+			}),
+		)
+	}
+
+	cmd, err := cli.New(
+		"test",
+		cli.SubCommands(help),
+		cli.Stdout(stdout),
+		cli.Stderr(io.Discard),
+		cli.OverrideArgs([]string{"help"}),
+	)
+	test.Ok(t, err)
+
+	test.Ok(t, cmd.Execute(t.Context()))
+	test.Equal(t, stdout.String(), "custom help\n")
+}
+
 func TestVersion(t *testing.T) {
 	sub1 := func() (*cli.Command, error) {
 		return cli.New(
@@ -828,6 +1129,31 @@ func TestOptionValidation(t *testing.T) {
 			options: []cli.Option{cli.Long("")},
 			errMsg:  "cannot set command long description to an empty string",
 		},
+		{
+			name:    "empty group",
+			options: []cli.Option{cli.Group("")},
+			errMsg:  "cannot set command group to an empty string",
+		},
+		{
+			name: "empty flag group",
+			options: []cli.Option{
+				cli.Flag(new(int), "count", 'c', "Count something", cli.FlagGroup[int]("")),
+			},
+			errMsg: "could not apply flag option: flag group cannot be empty",
+		},
+		{
+			name:    "help topic bad name",
+			options: []cli.Option{cli.HelpTopic("bad name", "short", "body")},
+			errMsg:  `help topic name "bad name" cannot contain whitespace`,
+		},
+		{
+			name: "help topic already exists",
+			options: []cli.Option{
+				cli.HelpTopic("environment", "short", "body"),
+				cli.HelpTopic("environment", "short again", "body again"),
+			},
+			errMsg: `help topic "environment" already defined`,
+		},
 		{
 			name:    "empty arg name",
 			options: []cli.Option{cli.Arg(new(string), "", "empty required arg")},
@@ -838,6 +1164,101 @@ func TestOptionValidation(t *testing.T) {
 			options: []cli.Option{cli.Arg(new(string), "a space", "some space things")},
 			errMsg:  `invalid arg name "a space": cannot contain whitespace`,
 		},
+		{
+			name:    "empty choices",
+			options: []cli.Option{cli.Flag(new(string), "format", 'f', "Output format", cli.Choices[string]())},
+			errMsg:  "could not apply flag option: flag choices cannot be empty",
+		},
+		{
+			name:    "choices on slice flag",
+			options: []cli.Option{cli.Flag(new([]string), "items", 'i', "Some items", cli.Choices([]string{"a"}))},
+			errMsg:  `flag "items": choices are not supported for slice flags`,
+		},
+		{
+			name:    "empty arg choices",
+			options: []cli.Option{cli.Arg(new(string), "shell", "The shell", cli.ArgChoices[string]())},
+			errMsg:  "could not apply arg option: argument choices cannot be empty",
+		},
+		{
+			name:    "empty validators",
+			options: []cli.Option{cli.Flag(new(int), "port", 'p', "Port", cli.Validate[int]())},
+			errMsg:  "could not apply flag option: flag validators cannot be empty",
+		},
+		{
+			name:    "nil validator",
+			options: []cli.Option{cli.Flag(new(int), "port", 'p', "Port", cli.Validate[int](nil))},
+			errMsg:  "could not apply flag option: flag validators cannot be nil",
+		},
+		{
+			name:    "nil arg validator",
+			options: []cli.Option{cli.Arg(new(string), "dir", "The dir", cli.ArgValidate[string](nil))},
+			errMsg:  "could not apply arg option: argument validators cannot be nil",
+		},
+		{
+			name:    "empty deprecation message",
+			options: []cli.Option{cli.Flag(new(string), "addr", 'a', "Address", cli.DeprecatedFlag[string](""))},
+			errMsg:  "could not apply flag option: deprecation message cannot be empty",
+		},
+		{
+			name:    "replaced by missing flag",
+			options: []cli.Option{cli.Flag(new(string), "addr", 'a', "Address", cli.ReplacedBy[string]("listen"))},
+			errMsg:  `flag "addr" is replaced by flag "listen" which does not exist`,
+		},
+		{
+			name:    "replaced by itself",
+			options: []cli.Option{cli.Flag(new(string), "addr", 'a', "Address", cli.ReplacedBy[string]("addr"))},
+			errMsg:  `flag "addr" cannot be replaced by itself`,
+		},
+		{
+			name: "no-arg default not a choice",
+			options: []cli.Option{
+				cli.Flag(new(string), "colour", 'c', "Colour", cli.Choices("auto", "never"), cli.NoArgDefault[string]("always")),
+			},
+			errMsg: `flag "colour": no-arg default "always" is not one of the choices [auto never]`,
+		},
+		{
+			name:    "empty delimiter",
+			options: []cli.Option{cli.Flag(new([]string), "items", 'i', "Items", cli.Delimiter[[]string](""))},
+			errMsg:  "could not apply flag option: delimiter cannot be empty",
+		},
+		{
+			name:    "delimiter with quote",
+			options: []cli.Option{cli.Flag(new([]string), "items", 'i', "Items", cli.Delimiter[[]string](`"`))},
+			errMsg:  `could not apply flag option: delimiter "\"" cannot contain quotes or backslashes`,
+		},
+		{
+			name:    "delimiter on non slice",
+			options: []cli.Option{cli.Flag(new(string), "name", 'n', "Name", cli.Delimiter[string](","))},
+			errMsg:  `flag "name": delimiters are only supported for slice flags`,
+		},
+		{
+			name:    "replace default on non slice",
+			options: []cli.Option{cli.Flag(new(string), "name", 'n', "Name", cli.ReplaceDefault[string]())},
+			errMsg:  `flag "name": replacing the default is only supported for slice flags`,
+		},
+		{
+			name:    "empty alias",
+			options: []cli.Option{cli.Flag(new(string), "colour", 'c', "Colour", cli.FlagAlias[string](""))},
+			errMsg:  "could not apply flag option: flag alias cannot be empty",
+		},
+		{
+			name:    "invalid alias",
+			options: []cli.Option{cli.Flag(new(string), "colour", 'c', "Colour", cli.FlagAlias[string]("Color"))},
+			errMsg:  `invalid alias "Color" for flag "colour": contains upper case character "C"`,
+		},
+		{
+			name:    "alias of itself",
+			options: []cli.Option{cli.Flag(new(string), "colour", 'c', "Colour", cli.FlagAlias[string]("colour"))},
+			errMsg:  `flag "colour": duplicate alias "colour"`,
+		},
+		{
+			name: "alias clashes with another flag",
+			options: []cli.Option{
+				cli.Flag(new(string), "colour", 'c', "Colour", cli.FlagAlias[string]("color")),
+				cli.Flag(new(string), "color", flag.NoShortHand, "Colour"),
+			},
+			errMsg: `flag "color" already defined as an alias of flag "colour"`,
+		},
 	}
 
 	for _, tt := range tests {
@@ -983,33 +1404,2150 @@ func TestEnvFlag(t *testing.T) {
 	}
 }
 
-// The order in which we apply options shouldn't matter, this test
-// shuffles the order of the options and asserts the Command we get
-// out behaves the same as a baseline.
-func TestCommandOptionOrder(t *testing.T) {
-	baseLineStdout := &bytes.Buffer{}
-	baseLineStderr := &bytes.Buffer{}
+func TestPager(t *testing.T) {
+	// Output to something that's not a terminal should never be paged,
+	// no matter how it's configured
+	t.Setenv("TEST_PAGER", "definitely-not-a-real-pager")
+
+	t.Run("help", func(t *testing.T) {
+		stdout := &bytes.Buffer{}
+		stderr := &bytes.Buffer{}
+
+		cmd, err := cli.New(
+			"test",
+			cli.Pager(true),
+			cli.Short("A test command"),
+			cli.Stdout(stdout),
+			cli.Stderr(stderr),
+			cli.OverrideArgs([]string{"--help"}),
+			cli.Run(func(ctx context.Context, cmd *cli.Command) error { return nil }),
+		)
+		test.Ok(t, err)
 
-	shuffleStdout := &bytes.Buffer{}
-	shuffleStderr := &bytes.Buffer{}
+		test.Ok(t, cmd.Execute(t.Context()))
+		test.Equal(t, stdout.String(), "")
+		test.True(t, strings.HasPrefix(stderr.String(), "A test command\n"))
+	})
 
-	var (
-		f     bool
-		count int
-	)
+	t.Run("run", func(t *testing.T) {
+		stdout := &bytes.Buffer{}
 
-	sub := func() (*cli.Command, error) {
-		return cli.New(
-			"sub",
+		cmd, err := cli.New(
+			"test",
+			cli.Pager(true),
+			cli.Stdout(stdout),
+			cli.OverrideArgs([]string{}),
 			cli.Run(func(ctx context.Context, cmd *cli.Command) error {
-				fmt.Fprintln(cmd.Stdout(), "Hello from sub")
+				w := cmd.Pager()
+				fmt.Fprintln(w, "Lots of output")
 
-				return nil
+				return w.Close()
 			}),
 		)
+		test.Ok(t, err)
+
+		test.Ok(t, cmd.Execute(t.Context()))
+		test.Equal(t, stdout.String(), "Lots of output\n")
+	})
+}
+
+func TestResponseFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"args.txt":    "# Serve things\nserve 8080\n'my server' # trailing comment\n@" + filepath.Join(dir, "more.txt") + "\n",
+		"more.txt":    "more\n",
+		"bad.txt":     "serve\n--name 'oops\n",
+		"self.txt":    "@" + filepath.Join(dir, "self.txt") + "\n",
+		"nested.txt":  "serve\n@" + filepath.Join(dir, "missing.txt") + "\n",
+		"crlf.txt":    "serve\r\n8080\r\n",
+		"long.txt":    "serve " + strings.Repeat("x", 100*1024) + "\n",
+		"literal.txt": "serve\n--\n@" + filepath.Join(dir, "more.txt") + "\n",
 	}
 
-	options := []cli.Option{
+	for name, contents := range files {
+		test.Ok(t, os.WriteFile(filepath.Join(dir, name), []byte(contents), 0o644))
+	}
+
+	tests := []struct {
+		name    string   // Name of the test case
+		errMsg  string   // If we wanted an error, what should it say
+		args    []string // Arguments to execute with
+		want    []string // Expected raw arguments seen by the run function
+		enabled bool     // Whether response files are enabled
+		wantErr bool     // Whether we want an error
+	}{
+		{
+			name:    "disabled",
+			args:    []string{"@" + filepath.Join(dir, "args.txt")},
+			enabled: false,
+			want:    []string{"@" + filepath.Join(dir, "args.txt")},
+		},
+		{
+			name:    "expanded",
+			args:    []string{"before", "@" + filepath.Join(dir, "args.txt"), "after"},
+			enabled: true,
+			want:    []string{"before", "serve", "8080", "my server", "more", "after"},
+		},
+		{
+			name:    "windows line endings",
+			args:    []string{"@" + filepath.Join(dir, "crlf.txt")},
+			enabled: true,
+			want:    []string{"serve", "8080"},
+		},
+		{
+			name:    "long line",
+			args:    []string{"@" + filepath.Join(dir, "long.txt")},
+			enabled: true,
+			want:    []string{"serve", strings.Repeat("x", 100*1024)},
+		},
+		{
+			name:    "lone at",
+			args:    []string{"@"},
+			enabled: true,
+			want:    []string{"@"},
+		},
+		{
+			name:    "after terminator",
+			args:    []string{"--", "@" + filepath.Join(dir, "more.txt")},
+			enabled: true,
+			want:    []string{"@" + filepath.Join(dir, "more.txt")},
+		},
+		{
+			name:    "terminator in file",
+			args:    []string{"@" + filepath.Join(dir, "literal.txt")},
+			enabled: true,
+			want:    []string{"serve", "@" + filepath.Join(dir, "more.txt")},
+		},
+		{
+			name:    "missing",
+			args:    []string{"@" + filepath.Join(dir, "missing.txt")},
+			enabled: true,
+			wantErr: true,
+			errMsg: "could not read response file " + filepath.Join(dir, "missing.txt") + ": open " +
+				filepath.Join(dir, "missing.txt") + ": no such file or directory",
+		},
+		{
+			name:    "bad quoting",
+			args:    []string{"@" + filepath.Join(dir, "bad.txt")},
+			enabled: true,
+			wantErr: true,
+			errMsg:  "response file " + filepath.Join(dir, "bad.txt") + ":2: unterminated ' quote",
+		},
+		{
+			name:    "recursive",
+			args:    []string{"@" + filepath.Join(dir, "self.txt")},
+			enabled: true,
+			wantErr: true,
+			errMsg: "response file " + filepath.Join(dir, "self.txt") + ": response file " +
+				filepath.Join(dir, "self.txt") + " is included recursively",
+		},
+		{
+			name:    "nested missing",
+			args:    []string{"@" + filepath.Join(dir, "nested.txt")},
+			enabled: true,
+			wantErr: true,
+			errMsg: "response file " + filepath.Join(dir, "nested.txt") + ": could not read response file " +
+				filepath.Join(dir, "missing.txt") + ": open " + filepath.Join(dir, "missing.txt") + ": no such file or directory",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+
+			options := []cli.Option{
+				cli.OverrideArgs(tt.args),
+				cli.Stderr(io.Discard),
+				cli.Run(func(ctx context.Context, cmd *cli.Command) error {
+					got = cmd.Args()
+					return nil
+				}),
+			}
+
+			if tt.enabled {
+				options = append(options, cli.ResponseFiles())
+			}
+
+			cmd, err := cli.New("test", options...)
+			test.Ok(t, err)
+
+			err = cmd.Execute(t.Context())
+			test.WantErr(t, err, tt.wantErr)
+
+			if err != nil {
+				test.Equal(t, err.Error(), tt.errMsg)
+				return
+			}
+
+			test.EqualFunc(t, got, tt.want, slices.Equal)
+		})
+	}
+}
+
+func TestFromFile(t *testing.T) {
+	dir := t.TempDir()
+
+	token := filepath.Join(dir, "token")
+	test.Ok(t, os.WriteFile(token, []byte("hunter2\n"), 0o600))
+
+	items := filepath.Join(dir, "items")
+	test.Ok(t, os.WriteFile(items, []byte("one\ntwo\n\nthree\n"), 0o600))
+
+	type options struct {
+		token    string
+		plain    string
+		manifest string
+		items    []string
+	}
+
+	tests := []struct {
+		name    string   // Name of the test case
+		stdin   string   // Contents of stdin
+		errMsg  string   // If we wanted an error, what should it say
+		args    []string // Arguments to execute with
+		want    options  // Expected values
+		wantErr bool     // Whether we want an error
+	}{
+		{
+			name: "literal values",
+			args: []string{"--token", "abc", "manifest"},
+			want: options{token: "abc", manifest: "manifest"},
+		},
+		{
+			name: "flag from file",
+			args: []string{"--token", "@" + token, "manifest"},
+			want: options{token: "hunter2", manifest: "manifest"},
+		},
+		{
+			name:  "flag from stdin",
+			args:  []string{"--token=-", "manifest"},
+			stdin: "from stdin\n",
+			want:  options{token: "from stdin", manifest: "manifest"},
+		},
+		{
+			name: "short flag from file",
+			args: []string{"-t", "@" + token, "manifest"},
+			want: options{token: "hunter2", manifest: "manifest"},
+		},
+		{
+			name: "slice from file",
+			args: []string{"--items", "zero", "--items", "@" + items, "manifest"},
+			want: options{items: []string{"zero", "one", "two", "three"}, manifest: "manifest"},
+		},
+		{
+			name: "not enabled",
+			args: []string{"--plain", "@" + token, "manifest"},
+			want: options{plain: "@" + token, manifest: "manifest"},
+		},
+		{
+			name: "arg from file",
+			args: []string{"@" + token},
+			want: options{manifest: "hunter2"},
+		},
+		{
+			name:  "arg from stdin",
+			args:  []string{"-"},
+			stdin: "kind: Deployment\n",
+			want:  options{manifest: "kind: Deployment"},
+		},
+		{
+			name:    "missing file",
+			args:    []string{"--token", "@" + filepath.Join(dir, "missing"), "manifest"},
+			wantErr: true,
+			errMsg: "failed to parse command flags: flag --token: could not read value from file: open " +
+				filepath.Join(dir, "missing") + ": no such file or directory",
+		},
+		{
+			name:    "arg missing file",
+			args:    []string{"@" + filepath.Join(dir, "missing")},
+			wantErr: true,
+			errMsg: `argument "manifest": could not read value from file: open ` +
+				filepath.Join(dir, "missing") + ": no such file or directory",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got options
+
+			cmd, err := cli.New(
+				"test",
+				cli.Stdin(strings.NewReader(tt.stdin)),
+				cli.OverrideArgs(tt.args),
+				cli.Flag(&got.token, "token", 't', "A secret token", cli.FromFile[string]()),
+				cli.Flag(&got.plain, "plain", flag.NoShortHand, "Not from a file"),
+				cli.Flag(&got.items, "items", flag.NoShortHand, "Some items", cli.FromFile[[]string]()),
+				cli.Arg(&got.manifest, "manifest", "A manifest", cli.ArgFromFile[string]()),
+				cli.Run(func(ctx context.Context, cmd *cli.Command) error { return nil }),
+			)
+			test.Ok(t, err)
+
+			err = cmd.Execute(t.Context())
+			test.WantErr(t, err, tt.wantErr)
+
+			if err != nil {
+				test.Equal(t, err.Error(), tt.errMsg)
+				return
+			}
+
+			test.Equal(t, got.token, tt.want.token)
+			test.Equal(t, got.plain, tt.want.plain)
+			test.Equal(t, got.manifest, tt.want.manifest)
+			test.EqualFunc(t, got.items, tt.want.items, slices.Equal)
+		})
+	}
+}
+
+func TestPrompt(t *testing.T) {
+	type options struct {
+		env   string
+		token string
+		name  string
+		force bool
+	}
+
+	tests := []struct {
+		name     string   // Name of the test case
+		stdin    string   // What the user types
+		stderr   string   // Expected prompts written to stderr
+		errMsg   string   // If we wanted an error, what should it say
+		args     []string // Arguments to execute with
+		envVars  []string // Pairs of key, value environment variables to set
+		want     options  // Expected values after execution
+		wantErr  bool     // Whether we want an error
+		noStderr bool     // Whether nothing should have been prompted
+	}{
+		{
+			name:  "all prompted",
+			args:  []string{},
+			stdin: "3\ny\nhunter2\nmyproject\n",
+			want:  options{env: "prod", force: true, token: "hunter2", name: "myproject"},
+			stderr: "? Environment to deploy to\n  1) dev\n  2) staging\n  3) prod\nChoose 1-3 (dev): " +
+				"? Force the deployment [y/N]: ? API token: ? Name of the project: ",
+		},
+		{
+			name:     "all given",
+			args:     []string{"--env", "staging", "--force", "--token", "abc", "myproject"},
+			stdin:    "should not be read\n",
+			want:     options{env: "staging", force: true, token: "abc", name: "myproject"},
+			noStderr: true,
+		},
+		{
+			name:     "from env",
+			args:     []string{"--force=false", "--token", "abc", "myproject"},
+			envVars:  []string{"TEST_PROMPT_ENV", "prod"},
+			stdin:    "should not be read\n",
+			want:     options{env: "prod", force: false, token: "abc", name: "myproject"},
+			noStderr: true,
+		},
+		{
+			name:     "no input",
+			args:     []string{"--no-input", "myproject"},
+			stdin:    "should not be read\n",
+			want:     options{env: "dev", name: "myproject"},
+			noStderr: true,
+		},
+		{
+			name:  "empty answers keep defaults",
+			args:  []string{"myproject"},
+			stdin: "\n\n\n",
+			want:  options{env: "dev", force: false, token: "", name: "myproject"},
+		},
+		{
+			name:  "choice by name",
+			args:  []string{"--force", "--token", "abc", "myproject"},
+			stdin: "staging\n",
+			want:  options{env: "staging", force: true, token: "abc", name: "myproject"},
+		},
+		{
+			name:  "invalid answers ask again",
+			args:  []string{"--token", "abc", "myproject"},
+			stdin: "qa\n1\nmaybe\nno\n",
+			want:  options{env: "dev", force: false, token: "abc", name: "myproject"},
+			stderr: "? Environment to deploy to\n  1) dev\n  2) staging\n  3) prod\nChoose 1-3 (dev): " +
+				"  parse error: flag \"env\" received invalid value \"qa\" (expected string): must be one of dev, staging, prod\n" +
+				"? Environment to deploy to\n  1) dev\n  2) staging\n  3) prod\nChoose 1-3 (dev): " +
+				"? Force the deployment [y/N]:   please answer yes or no, got \"maybe\"\n" +
+				"? Force the deployment [y/N]: ",
+		},
+		{
+			name:  "required arg asks again",
+			args:  []string{"--no-input=false", "--env", "dev", "--force", "--token", "abc"},
+			stdin: "\nmyproject\n",
+			want:  options{env: "dev", force: true, token: "abc", name: "myproject"},
+			stderr: "? Name of the project: " +
+				"  A value is required\n" +
+				"? Name of the project: ",
+		},
+		{
+			name:    "required arg no answer",
+			args:    []string{"--env", "dev", "--force", "--token", "abc"},
+			stdin:   "",
+			wantErr: true,
+			errMsg:  `argument "name" is required and no value was provided`,
+		},
+		{
+			name:    "invalid choice on command line",
+			args:    []string{"--env", "qa", "myproject"},
+			wantErr: true,
+			errMsg: `failed to parse command flags: parse error: flag "env" received invalid value "qa" ` +
+				"(expected string): must be one of dev, staging, prod",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TEST_PROMPT_ENV", "")
+
+			for i := 0; i+1 < len(tt.envVars); i += 2 {
+				t.Setenv(tt.envVars[i], tt.envVars[i+1])
+			}
+
+			stderr := &bytes.Buffer{}
+
+			var got options
+
+			cmd, err := cli.New(
+				"test",
+				cli.Stdin(strings.NewReader(tt.stdin)),
+				cli.Interactive(true),
+				cli.Stderr(stderr),
+				cli.OverrideArgs(tt.args),
+				cli.Flag(
+					&got.env,
+					"env",
+					'e',
+					"Environment to deploy to",
+					cli.FlagDefault("dev"),
+					cli.Prompt[string](),
+					cli.Choices("dev", "staging", "prod"),
+					cli.Env[string]("TEST_PROMPT_ENV"),
+				),
+				cli.Flag(&got.force, "force", 'f', "Force the deployment", cli.Prompt[bool]()),
+				cli.Flag(&got.token, "token", 't', "API token", cli.Prompt[string](), cli.Sensitive[string]()),
+				cli.Arg(&got.name, "name", "Name of the project", cli.ArgPrompt[string]()),
+				cli.Run(func(ctx context.Context, cmd *cli.Command) error { return nil }),
+			)
+			test.Ok(t, err)
+
+			err = cmd.Execute(t.Context())
+			test.WantErr(t, err, tt.wantErr)
+
+			if err != nil {
+				test.Equal(t, err.Error(), tt.errMsg)
+				return
+			}
+
+			test.Equal(t, got, tt.want)
+
+			if tt.noStderr {
+				test.Equal(t, stderr.String(), "")
+			}
+
+			if tt.stderr != "" {
+				test.Equal(t, stderr.String(), tt.stderr)
+			}
+		})
+	}
+
+	t.Run("not a terminal", func(t *testing.T) {
+		// A file that isn't a terminal e.g. piped input, should never be prompted from
+		stdin, err := os.Open(os.DevNull)
+		test.Ok(t, err)
+
+		defer stdin.Close()
+
+		var name string
+
+		cmd, err := cli.New(
+			"test",
+			cli.Stdin(stdin),
+			cli.Stderr(io.Discard),
+			cli.OverrideArgs([]string{}),
+			cli.Arg(&name, "name", "Name of the project", cli.ArgPrompt[string]()),
+			cli.Run(func(ctx context.Context, cmd *cli.Command) error { return nil }),
+		)
+		test.Ok(t, err)
+
+		var missing *cli.MissingArgumentError
+		test.True(t, errors.As(cmd.Execute(t.Context()), &missing))
+	})
+
+	t.Run("wrapped stdin", func(t *testing.T) {
+		// Any reader other than a terminal, even one wrapping it, is not interactive
+		// unless the command opts in
+		var name string
+
+		cmd, err := cli.New(
+			"test",
+			cli.Stdin(bufio.NewReader(strings.NewReader("myproject\n"))),
+			cli.Stderr(io.Discard),
+			cli.OverrideArgs([]string{}),
+			cli.Arg(&name, "name", "Name of the project", cli.ArgPrompt[string]()),
+			cli.Run(func(ctx context.Context, cmd *cli.Command) error { return nil }),
+		)
+		test.Ok(t, err)
+
+		var missing *cli.MissingArgumentError
+		test.True(t, errors.As(cmd.Execute(t.Context()), &missing))
+	})
+
+	t.Run("secret answers are redacted", func(t *testing.T) {
+		stderr := &bytes.Buffer{}
+
+		var pin int
+
+		cmd, err := cli.New(
+			"test",
+			cli.Stdin(strings.NewReader("hunter2\n1234\n")),
+			cli.Interactive(true),
+			cli.Stderr(stderr),
+			cli.OverrideArgs([]string{}),
+			cli.Flag(&pin, "pin", 'p', "Your PIN", cli.Prompt[int](), cli.Sensitive[int]()),
+			cli.Run(func(ctx context.Context, cmd *cli.Command) error { return nil }),
+		)
+		test.Ok(t, err)
+
+		test.Ok(t, cmd.Execute(t.Context()))
+		test.Equal(t, pin, 1234)
+		test.False(t, strings.Contains(stderr.String(), "hunter2"))
+	})
+
+	t.Run("answers are set like the command line", func(t *testing.T) {
+		var (
+			listen string
+			addr   string
+			tags   []string
+		)
+
+		cmd, err := cli.New(
+			"test",
+			cli.Stdin(strings.NewReader(":9000\nx,y\n")),
+			cli.Interactive(true),
+			cli.Stderr(io.Discard),
+			cli.OverrideArgs([]string{}),
+			cli.Flag(&addr, "addr", flag.NoShortHand, "Address", cli.Prompt[string](), cli.ReplacedBy[string]("listen")),
+			cli.Flag(&listen, "listen", 'l', "Address to listen on"),
+			cli.Flag(
+				&tags,
+				"tag",
+				't',
+				"Tags",
+				cli.Prompt[[]string](),
+				cli.FlagDefault([]string{"default"}),
+				cli.Delimiter[[]string](","),
+			),
+			cli.Run(func(ctx context.Context, cmd *cli.Command) error { return nil }),
+		)
+		test.Ok(t, err)
+
+		test.Ok(t, cmd.Execute(t.Context()))
+		test.Equal(t, addr, ":9000")
+		test.Equal(t, listen, ":9000")
+		test.EqualFunc(t, tags, []string{"x", "y"}, slices.Equal)
+	})
+}
+
+func TestShell(t *testing.T) {
+	tests := []struct {
+		name   string   // Name of the test case
+		stdin  string   // Lines typed into the shell
+		stdout string   // Expected stdout
+		stderr string   // Expected stderr
+		args   []string // Arguments to execute the root command with
+	}{
+		{
+			name:   "empty",
+			args:   []string{"shell"},
+			stdin:  "",
+			stdout: "",
+			stderr: "test> \n",
+		},
+		{
+			name:   "runs each line",
+			args:   []string{"shell"},
+			stdin:  "greet\ngreet --name bob\n\ngreet --name 'bob smith' --shout\n",
+			stdout: "hello world\nhello bob\nHELLO BOB SMITH\n",
+			stderr: "test> test> test> test> test> \n",
+		},
+		{
+			name:   "flags reset between lines",
+			args:   []string{"shell"},
+			stdin:  "greet --name bob --shout\ngreet\n",
+			stdout: "HELLO BOB\nhello world\n",
+			stderr: "test> test> test> \n",
+		},
+		{
+			name:   "exit",
+			args:   []string{"shell"},
+			stdin:  "greet\nexit\ngreet\n",
+			stdout: "hello world\n",
+			stderr: "test> test> ",
+		},
+		{
+			name:   "errors carry on",
+			args:   []string{"shell"},
+			stdin:  "greet --nope\ngreet 'unterminated\nshell\ngreet\n",
+			stdout: "hello world\n",
+			stderr: "test> error: failed to parse command flags: unrecognised flag: --nope\n" +
+				"test> error: unterminated ' quote\n" +
+				"test> error: already in a shell\n" +
+				"test> test> \n",
+		},
+		{
+			name:   "history",
+			args:   []string{"shell"},
+			stdin:  "greet\n  greet --name bob\nhistory\n",
+			stdout: "hello world\nhello bob\n    1  greet\n    2  greet --name bob\n    3  history\n",
+			stderr: "test> test> test> test> \n",
+		},
+		{
+			name:   "nested",
+			args:   []string{"nested", "shell"},
+			stdin:  "inner\n",
+			stdout: "inner called\n",
+			stderr: "test nested> test nested> \n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			var (
+				name  string
+				shout bool
+			)
+
+			greet := func() (*cli.Command, error) {
+				return cli.New(
+					"greet",
+					cli.Flag(&name, "name", 'n', "Who to greet", cli.FlagDefault("world")),
+					cli.Flag(&shout, "shout", 's', "Greet loudly"),
+					cli.Run(func(ctx context.Context, cmd *cli.Command) error {
+						greeting := "hello " + name
+						if shout {
+							greeting = strings.ToUpper(greeting)
+						}
+
+						fmt.Fprintln(cmd.Stdout(), greeting)
+
+						return nil
+					}),
+				)
+			}
+
+			inner := func() (*cli.Command, error) {
+				return cli.New(
+					"inner",
+					cli.Run(func(ctx context.Context, cmd *cli.Command) error {
+						fmt.Fprintln(cmd.Stdout(), "inner called")
+						return nil
+					}),
+				)
+			}
+
+			nested := func() (*cli.Command, error) {
+				return cli.New("nested", cli.Shell(), cli.SubCommands(inner))
+			}
+
+			cmd, err := cli.New(
+				"test",
+				cli.Shell(),
+				cli.Stdin(strings.NewReader(tt.stdin)),
+				cli.Stdout(stdout),
+				cli.Stderr(stderr),
+				cli.OverrideArgs(tt.args),
+				cli.SubCommands(greet, nested),
+			)
+			test.Ok(t, err)
+
+			test.Ok(t, cmd.Execute(t.Context()))
+
+			test.Equal(t, stdout.String(), tt.stdout)
+			test.Equal(t, stderr.String(), tt.stderr)
+		})
+	}
+
+	t.Run("help lists built-ins", func(t *testing.T) {
+		stderr := &bytes.Buffer{}
+
+		cmd, err := cli.New(
+			"test",
+			cli.Shell(),
+			cli.Stdin(strings.NewReader("help\n")),
+			cli.Stderr(stderr),
+			cli.OverrideArgs([]string{"shell"}),
+			cli.Run(func(ctx context.Context, cmd *cli.Command) error { return nil }),
+		)
+		test.Ok(t, err)
+
+		test.Ok(t, cmd.Execute(t.Context()))
+
+		test.True(t, strings.Contains(stderr.String(), "Usage:"))
+		test.True(t, strings.Contains(stderr.String(), "Shell Commands:\n\n  exit     Leave the shell\n"))
+	})
+
+	t.Run("conflicting subcommand", func(t *testing.T) {
+		shell := func() (*cli.Command, error) {
+			return cli.New("shell", cli.Run(func(ctx context.Context, cmd *cli.Command) error { return nil }))
+		}
+
+		_, err := cli.New("test", cli.Shell(), cli.SubCommands(shell))
+		test.Err(t, err)
+		test.Equal(t, err.Error(), `command test: cannot use the Shell option with a subcommand named "shell"`)
+	})
+}
+
+func TestPlugins(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test plugins are shell scripts")
+	}
+
+	// Two directories on $PATH, the first plugin found for a name should win
+	first := t.TempDir()
+	second := t.TempDir()
+
+	plugins := map[string]string{
+		filepath.Join(first, "test-hello"):   "#!/bin/sh\necho \"hello from plugin: $*\"\n",
+		filepath.Join(first, "test-fail"):    "#!/bin/sh\necho \"plugin failed\" >&2\nexit 3\n",
+		filepath.Join(first, "test-stdin"):   "#!/bin/sh\nread line\necho \"got $line\"\n",
+		filepath.Join(second, "test-hello"):  "#!/bin/sh\necho \"wrong hello\"\n",
+		filepath.Join(second, "test-sub"):    "#!/bin/sh\necho \"shadowed by a subcommand\"\n",
+		filepath.Join(second, "test-noexec"): "#!/bin/sh\necho \"not executable\"\n",
+	}
+
+	for path, script := range plugins {
+		perm := os.FileMode(0o755)
+		if strings.HasSuffix(path, "noexec") {
+			perm = 0o644
+		}
+
+		test.Ok(t, os.WriteFile(path, []byte(script), perm))
+	}
+
+	t.Setenv("PATH", first+string(os.PathListSeparator)+second)
+
+	tests := []struct {
+		name     string   // Name of the test case
+		stdin    string   // Stdin for the command
+		stdout   string   // Expected stdout
+		stderr   string   // Expected stderr
+		errMsg   string   // If we wanted an error, what should it say
+		args     []string // Arguments to execute with
+		exitCode int      // Expected exit code if there was an error
+		wantErr  bool     // Whether we want an error
+	}{
+		{
+			name:   "run plugin",
+			args:   []string{"hello", "--name", "world", "extra"},
+			stdout: "hello from plugin: --name world extra\n",
+		},
+		{
+			name:   "flags before plugin",
+			args:   []string{"--verbose", "hello", "there"},
+			stdout: "hello from plugin: there\n",
+		},
+		{
+			name:   "subcommands take priority",
+			args:   []string{"sub"},
+			stdout: "sub called\n",
+		},
+		{
+			name:   "stdin",
+			args:   []string{"stdin"},
+			stdin:  "some input\n",
+			stdout: "got some input\n",
+		},
+		{
+			name:     "exit code",
+			args:     []string{"fail"},
+			stderr:   "plugin failed\n",
+			wantErr:  true,
+			errMsg:   "plugin test-fail: exit status 3",
+			exitCode: 3,
+		},
+		{
+			name:    "not executable",
+			args:    []string{"noexec"},
+			wantErr: true,
+			errMsg:  "unknown command \"noexec\" for \"test\"\n\nRun 'test --help' for usage.",
+		},
+		{
+			name:    "path traversal",
+			args:    []string{"../test-hello"},
+			wantErr: true,
+			errMsg:  "unknown command \"../test-hello\" for \"test\"\n\nRun 'test --help' for usage.",
+		},
+		{
+			name:   "plugins list",
+			args:   []string{"plugins", "list"},
+			stdout: "fail   " + filepath.Join(first, "test-fail") + "\nhello  " + filepath.Join(first, "test-hello") + "\nstdin  " + filepath.Join(first, "test-stdin") + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			sub := func() (*cli.Command, error) {
+				return cli.New(
+					"sub",
+					cli.Run(func(ctx context.Context, cmd *cli.Command) error {
+						fmt.Fprintln(cmd.Stdout(), "sub called")
+						return nil
+					}),
+				)
+			}
+
+			var verbose bool
+
+			cmd, err := cli.New(
+				"test",
+				cli.Plugins("test"),
+				cli.Stdin(strings.NewReader(tt.stdin)),
+				cli.Stdout(stdout),
+				cli.Stderr(stderr),
+				cli.OverrideArgs(tt.args),
+				cli.Flag(&verbose, "verbose", 'v', "Show more output"),
+				cli.SubCommands(sub),
+			)
+			test.Ok(t, err)
+
+			err = cmd.Execute(t.Context())
+			test.WantErr(t, err, tt.wantErr)
+
+			if err != nil {
+				test.Equal(t, err.Error(), tt.errMsg)
+
+				if tt.exitCode != 0 {
+					var exitErr *exec.ExitError
+					test.True(t, errors.As(err, &exitErr))
+					test.Equal(t, exitErr.ExitCode(), tt.exitCode)
+				}
+			}
+
+			test.Equal(t, stdout.String(), tt.stdout)
+			test.Equal(t, stderr.String(), tt.stderr)
+		})
+	}
+
+	t.Run("help", func(t *testing.T) {
+		stderr := &bytes.Buffer{}
+
+		cmd, err := cli.New(
+			"test",
+			cli.Plugins("test"),
+			cli.Stderr(stderr),
+			cli.OverrideArgs([]string{"--help"}),
+			cli.Run(func(ctx context.Context, cmd *cli.Command) error { return nil }),
+		)
+		test.Ok(t, err)
+
+		test.Ok(t, cmd.Execute(t.Context()))
+
+		want := "Plugins:\n\n  fail   Run the test-fail plugin\n  hello  Run the test-hello plugin\n  stdin  Run the test-stdin plugin\n"
+		test.True(t, strings.Contains(stderr.String(), want))
+	})
+
+	t.Run("bad prefix", func(t *testing.T) {
+		_, err := cli.New("test", cli.Plugins("my tool"), cli.Run(func(ctx context.Context, cmd *cli.Command) error { return nil }))
+		test.Err(t, err)
+		test.Equal(t, err.Error(), `invalid plugin prefix "my tool", must not contain spaces or path separators`)
+	})
+}
+
+func TestLazy(t *testing.T) {
+	var built []string
+
+	lazy := func(name, group string, options ...cli.Option) cli.Builder {
+		return cli.Lazy(name, "The "+name+" command", group, func() (*cli.Command, error) {
+			built = append(built, name)
+
+			options = append(
+				options,
+				cli.Short("The real "+name+" command"),
+				cli.Run(func(ctx context.Context, cmd *cli.Command) error {
+					fmt.Fprintf(cmd.Stdout(), "%s called with %v\n", name, cmd.Args())
+					return nil
+				}),
+			)
+
+			return cli.New(name, options...)
+		})
+	}
+
+	var force bool
+
+	build := func(t *testing.T, args ...string) (*cli.Command, *bytes.Buffer, *bytes.Buffer) {
+		t.Helper()
+
+		if args == nil {
+			args = []string{}
+		}
+
+		built = nil
+		stdout := &bytes.Buffer{}
+		stderr := &bytes.Buffer{}
+
+		cmd, err := cli.New(
+			"test",
+			cli.Stdout(stdout),
+			cli.Stderr(stderr),
+			cli.OverrideArgs(args),
+			cli.SubCommands(
+				lazy("one", "", cli.Flag(&force, "force", 'f', "Force something")),
+				lazy("two", "Other Commands"),
+				lazy("three", "Other Commands", cli.Group("Other Commands")),
+				cli.Lazy("broken", "Fails to build", "", func() (*cli.Command, error) {
+					return nil, errors.New("bang")
+				}),
+				cli.Lazy("misnamed", "Builds the wrong command", "", func() (*cli.Command, error) {
+					return cli.New("other", cli.Run(func(ctx context.Context, cmd *cli.Command) error { return nil }))
+				}),
+				lazy("regrouped", "Other Commands", cli.Group("Different Commands")),
+			),
+		)
+		test.Ok(t, err)
+
+		return cmd, stdout, stderr
+	}
+
+	t.Run("nothing built up front", func(t *testing.T) {
+		_, _, _ = build(t)
+		test.Equal(t, len(built), 0)
+	})
+
+	t.Run("only requested command built", func(t *testing.T) {
+		cmd, stdout, _ := build(t, "one", "--force", "arg")
+
+		test.Ok(t, cmd.Execute(t.Context()))
+		test.EqualFunc(t, built, []string{"one"}, slices.Equal)
+		test.Equal(t, stdout.String(), "one called with [arg]\n")
+		test.True(t, force)
+
+		// Built once, not again on the next execution
+		test.Ok(t, cmd.Execute(t.Context()))
+		test.EqualFunc(t, built, []string{"one"}, slices.Equal)
+	})
+
+	t.Run("subcommand help", func(t *testing.T) {
+		cmd, _, stderr := build(t, "two", "--help")
+
+		test.Ok(t, cmd.Execute(t.Context()))
+		test.EqualFunc(t, built, []string{"two"}, slices.Equal)
+		test.True(t, strings.HasPrefix(stderr.String(), "The real two command\n"))
+	})
+
+	t.Run("parent help builds nothing", func(t *testing.T) {
+		cmd, _, stderr := build(t, "--help")
+
+		test.Ok(t, cmd.Execute(t.Context()))
+		test.Equal(t, len(built), 0)
+		test.True(t, strings.Contains(stderr.String(), "Fails to build\n"))
+		test.True(t, strings.Contains(stderr.String(), "Other Commands:\n\n  two        The two command\n"))
+		test.True(t, strings.Contains(stderr.String(), "  regrouped  The regrouped command\n"))
+	})
+
+	t.Run("builder error", func(t *testing.T) {
+		cmd, _, _ := build(t, "broken")
+
+		err := cmd.Execute(t.Context())
+		test.Err(t, err)
+		test.Equal(t, err.Error(), `could not build subcommand "broken": bang`)
+	})
+
+	t.Run("wrong name", func(t *testing.T) {
+		cmd, _, _ := build(t, "misnamed")
+
+		err := cmd.Execute(t.Context())
+		test.Err(t, err)
+		test.Equal(t, err.Error(), `lazy subcommand "misnamed" built a command named "other"`)
+	})
+
+	t.Run("wrong group", func(t *testing.T) {
+		cmd, _, _ := build(t, "regrouped")
+
+		err := cmd.Execute(t.Context())
+		test.Err(t, err)
+		test.Equal(t, err.Error(), `lazy subcommand "regrouped" in group "Other Commands" built a command in group "Different Commands"`)
+	})
+
+	t.Run("no input", func(t *testing.T) {
+		for _, isLazy := range []bool{false, true} {
+			var name string
+
+			greet := func() (*cli.Command, error) {
+				return cli.New(
+					"greet",
+					cli.Flag(&name, "name", 'n', "Who to greet", cli.Prompt[string]()),
+					cli.Run(func(ctx context.Context, cmd *cli.Command) error { return nil }),
+				)
+			}
+
+			sub := greet
+			if isLazy {
+				sub = cli.Lazy("greet", "Say hello", "", greet)
+			}
+
+			cmd, err := cli.New(
+				"test",
+				cli.Stdin(strings.NewReader("me\n")),
+				cli.Interactive(true),
+				cli.Stderr(io.Discard),
+				cli.OverrideArgs([]string{"greet", "--no-input"}),
+				cli.SubCommands(sub),
+			)
+			test.Ok(t, err)
+
+			test.Ok(t, cmd.Execute(t.Context()))
+			test.Equal(t, name, "") // --no-input means no prompt, lazy or not
+		}
+	})
+
+	t.Run("group kept when built", func(t *testing.T) {
+		built = nil
+
+		cmd, err := cli.New(
+			"test",
+			cli.Stdout(io.Discard),
+			cli.OverrideArgs([]string{"two"}),
+			cli.SubCommands(lazy("two", "Other Commands")),
+		)
+		test.Ok(t, err)
+		test.Ok(t, cmd.Execute(t.Context()))
+		test.EqualFunc(t, built, []string{"two"}, slices.Equal)
+
+		description, err := cmd.Describe()
+		test.Ok(t, err)
+
+		index := slices.IndexFunc(description.Commands, func(c spec.Command) bool { return c.Name == "two" })
+		test.True(t, index != -1)
+		test.Equal(t, description.Commands[index].Group, "Other Commands")
+	})
+}
+
+func TestRequired(t *testing.T) {
+	tests := []struct {
+		name    string   // Name of the test case
+		stdin   string   // What the user types, if prompted
+		errMsg  string   // If we wanted an error, what should it say
+		want    string   // Expected value of the flag
+		args    []string // Arguments to execute with
+		env     string   // Value of the flag's environment variable
+		wantErr bool     // Whether we want an error
+	}{
+		{
+			name: "given",
+			args: []string{"--token", "abc"},
+			want: "abc",
+		},
+		{
+			name: "from env",
+			args: []string{},
+			env:  "fromenv",
+			want: "fromenv",
+		},
+		{
+			name:    "missing",
+			args:    []string{},
+			wantErr: true,
+			errMsg:  "flag --token is required and no value was provided",
+		},
+		{
+			name:    "explicitly empty still counts",
+			args:    []string{"--token", ""},
+			wantErr: false,
+			want:    "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TEST_REQUIRED_TOKEN", tt.env)
+
+			var token string
+
+			cmd, err := cli.New(
+				"test",
+				cli.Stderr(io.Discard),
+				cli.OverrideArgs(tt.args),
+				cli.Flag(&token, "token", 't', "API token", cli.Required[string](), cli.Env[string]("TEST_REQUIRED_TOKEN")),
+				cli.Run(func(ctx context.Context, cmd *cli.Command) error { return nil }),
+			)
+			test.Ok(t, err)
+
+			err = cmd.Execute(t.Context())
+			test.WantErr(t, err, tt.wantErr)
+
+			if err != nil {
+				test.Equal(t, err.Error(), tt.errMsg)
+
+				var missing *cli.MissingFlagError
+				test.True(t, errors.As(err, &missing))
+				test.Equal(t, missing.Name, "token")
+
+				return
+			}
+
+			test.Equal(t, token, tt.want)
+		})
+	}
+
+	t.Run("prompted", func(t *testing.T) {
+		stderr := &bytes.Buffer{}
+
+		var token string
+
+		cmd, err := cli.New(
+			"test",
+			cli.Stdin(strings.NewReader("\nabc\n")),
+			cli.Interactive(true),
+			cli.Stderr(stderr),
+			cli.OverrideArgs([]string{}),
+			cli.Flag(&token, "token", 't', "API token", cli.Required[string](), cli.Prompt[string]()),
+			cli.Run(func(ctx context.Context, cmd *cli.Command) error { return nil }),
+		)
+		test.Ok(t, err)
+
+		test.Ok(t, cmd.Execute(t.Context()))
+		test.Equal(t, token, "abc")
+		test.Equal(t, stderr.String(), "? API token:   A value is required\n? API token: ")
+	})
+}
+
+func TestFlagsFrom(t *testing.T) {
+	type options struct {
+		Port       int           `cli:"short=p,env=TEST_FLAGS_FROM_PORT,usage=Port to bind,default=8080"`
+		Token      string        `cli:"usage=API token,required"`
+		Timeout    time.Duration `cli:"name=wait,usage=How long to wait\\, at most"`
+		Tags       []string      `cli:"usage=Tags to apply,default=a\\,b"`
+		MaxRetries int           `cli:"usage=Retries before giving up"`
+		Verbose    flag.Count    `cli:"short=v,usage=Increase verbosity"`
+		DB         struct {
+			Host string `cli:"usage=Database host,default=localhost"`
+			Port int    `cli:"usage=Database port"`
+		} `cli:"prefix=db,group=Database Options"`
+		File    string `arg:"usage=File to serve"`
+		Mode    string `arg:"usage=Serving mode,default=fast"`
+		Ignored string
+		private string `cli:"usage=Not exported"` //nolint:unused // Testing it's skipped
+	}
+
+	tests := []struct {
+		name    string            // Name of the test case
+		errMsg  string            // If we wanted an error, what should it say
+		args    []string          // Arguments to execute with
+		env     map[string]string // Environment variables to set
+		check   func(t *testing.T, opts options)
+		wantErr bool // Whether we want an error
+	}{
+		{
+			name: "defaults",
+			args: []string{"--token", "abc", "index.html"},
+			check: func(t *testing.T, opts options) {
+				test.Equal(t, opts.Port, 8080)
+				test.Equal(t, opts.Token, "abc")
+				test.Equal(t, opts.DB.Host, "localhost")
+				test.Equal(t, opts.File, "index.html")
+				test.Equal(t, opts.Mode, "fast")
+				test.EqualFunc(t, opts.Tags, []string{"a", "b"}, slices.Equal)
+			},
+		},
+		{
+			name: "everything given",
+			args: []string{
+				"-p", "9000", "--token", "abc", "--wait", "5s", "--tags", "x", "--max-retries", "3",
+				"-vvv", "--db-host", "db.internal", "--db-port", "5432", "index.html", "slow",
+			},
+			check: func(t *testing.T, opts options) {
+				test.Equal(t, opts.Port, 9000)
+				test.Equal(t, opts.Timeout, 5*time.Second)
+				test.EqualFunc(t, opts.Tags, []string{"a", "b", "x"}, slices.Equal)
+				test.Equal(t, opts.MaxRetries, 3)
+				test.Equal(t, opts.Verbose, flag.Count(3))
+				test.Equal(t, opts.DB.Host, "db.internal")
+				test.Equal(t, opts.DB.Port, 5432)
+				test.Equal(t, opts.Mode, "slow")
+			},
+		},
+		{
+			name: "env",
+			args: []string{"--token", "abc", "index.html"},
+			env:  map[string]string{"TEST_FLAGS_FROM_PORT": "1234"},
+			check: func(t *testing.T, opts options) {
+				test.Equal(t, opts.Port, 1234)
+			},
+		},
+		{
+			name:    "required",
+			args:    []string{"index.html"},
+			wantErr: true,
+			errMsg:  "flag --token is required and no value was provided",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TEST_FLAGS_FROM_PORT", "")
+
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			var opts options
+
+			cmd, err := cli.New(
+				"test",
+				cli.Stderr(io.Discard),
+				cli.OverrideArgs(tt.args),
+				cli.FlagsFrom(&opts),
+				cli.Run(func(ctx context.Context, cmd *cli.Command) error { return nil }),
+			)
+			test.Ok(t, err)
+
+			err = cmd.Execute(t.Context())
+			test.WantErr(t, err, tt.wantErr)
+
+			if err != nil {
+				test.Equal(t, err.Error(), tt.errMsg)
+				return
+			}
+
+			tt.check(t, opts)
+		})
+	}
+
+	t.Run("help", func(t *testing.T) {
+		stderr := &bytes.Buffer{}
+
+		var opts options
+
+		cmd, err := cli.New(
+			"test",
+			cli.Stderr(stderr),
+			cli.OverrideArgs([]string{"--help"}),
+			cli.FlagsFrom(&opts),
+			cli.Run(func(ctx context.Context, cmd *cli.Command) error { return nil }),
+		)
+		test.Ok(t, err)
+
+		test.Ok(t, cmd.Execute(t.Context()))
+
+		help := stderr.String()
+		test.True(t, strings.Contains(help, "Usage: test [OPTIONS] FILE [MODE]"))
+		test.True(t, strings.Contains(help, "How long to wait, at most"))
+		test.True(t, strings.Contains(help, "Database Options:\n\n  N/A  --db-host"))
+		test.True(t, strings.Contains(help, "[required]"))
+	})
+
+	t.Run("current values are defaults", func(t *testing.T) {
+		opts := options{MaxRetries: 5}
+
+		cmd, err := cli.New(
+			"test",
+			cli.OverrideArgs([]string{"--token", "abc", "index.html"}),
+			cli.FlagsFrom(&opts),
+			cli.Run(func(ctx context.Context, cmd *cli.Command) error { return nil }),
+		)
+		test.Ok(t, err)
+
+		test.Ok(t, cmd.Execute(t.Context()))
+		test.Equal(t, opts.MaxRetries, 5)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		var (
+			number  int
+			badType struct {
+				Channel chan int `cli:""`
+			}
+			badKey struct {
+				Name string `cli:"nope=1"`
+			}
+			dupKey struct {
+				Name string `cli:"usage=a,usage=b"`
+			}
+			badShort struct {
+				Name string `cli:"short=ab"`
+			}
+			badDef struct {
+				Count int `cli:"default=lots"`
+			}
+			bothTags struct {
+				Name string `cli:"" arg:""`
+			}
+			badArgDef struct {
+				Count int `arg:"default=lots"`
+			}
+		)
+
+		invalid := []struct {
+			opts   any
+			errMsg string
+		}{
+			{opts: nil, errMsg: "FlagsFrom requires a non-nil pointer to a struct, got <nil>"},
+			{opts: &number, errMsg: "FlagsFrom requires a non-nil pointer to a struct, got *int"},
+			{opts: badType, errMsg: "FlagsFrom requires a non-nil pointer to a struct, got struct { Channel chan int \"cli:\\\"\\\"\" }"},
+			{opts: &badType, errMsg: "FlagsFrom(*struct { Channel chan int \"cli:\\\"\\\"\" }): field Channel: type chan int cannot be a flag"},
+			{opts: &badKey, errMsg: `FlagsFrom(*struct { Name string "cli:\"nope=1\"" }): field Name: unknown tag key "nope", expected one of name, short, env, usage, default, required, group`},
+			{opts: &dupKey, errMsg: `FlagsFrom(*struct { Name string "cli:\"usage=a,usage=b\"" }): field Name: duplicate tag key "usage"`},
+			{opts: &badShort, errMsg: `FlagsFrom(*struct { Name string "cli:\"short=ab\"" }): field Name: short must be a single character, got "ab"`},
+			{
+				opts: &badDef,
+				errMsg: `FlagsFrom(*struct { Count int "cli:\"default=lots\"" }): field Count: bad default: parse error: ` +
+					`flag "count" received invalid value "lots" (expected int): strconv.ParseInt: parsing "lots": invalid syntax`,
+			},
+			{opts: &bothTags, errMsg: `FlagsFrom(*struct { Name string "cli:\"\" arg:\"\"" }): field Name: cannot have both "cli" and "arg" tags`},
+			{
+				opts: &badArgDef,
+				errMsg: `FlagsFrom(*struct { Count int "arg:\"default=lots\"" }): field Count: bad default: parse error: ` +
+					`argument "count" received invalid value "lots" (expected int): strconv.ParseInt: parsing "lots": invalid syntax`,
+			},
+		}
+
+		for _, tt := range invalid {
+			_, err := cli.New("test", cli.FlagsFrom(tt.opts), cli.Run(func(ctx context.Context, cmd *cli.Command) error { return nil }))
+			test.Err(t, err)
+			test.Equal(t, err.Error(), tt.errMsg)
+		}
+	})
+}
+
+func TestDeprecatedFlag(t *testing.T) {
+	type options struct {
+		addr   string
+		listen string
+		tags   []string
+		labels []string
+		trace  bool
+	}
+
+	build := func(t *testing.T, opts *options, stderr io.Writer, args []string) *cli.Command {
+		t.Helper()
+
+		cmd, err := cli.New(
+			"test",
+			cli.OverrideArgs(args),
+			cli.Stderr(stderr),
+			cli.Flag(&opts.listen, "listen", 'l', "Address to listen on", cli.FlagDefault(":8080")),
+			cli.Flag(
+				&opts.addr,
+				"addr",
+				'a',
+				"Address to listen on",
+				cli.Env[string]("TEST_DEPRECATED_ADDR"),
+				cli.ReplacedBy[string]("listen"),
+				cli.HiddenFlag[string](),
+			),
+			cli.Flag(&opts.labels, "label", flag.NoShortHand, "Labels to apply"),
+			cli.Flag(&opts.tags, "tag", flag.NoShortHand, "Tags to apply", cli.ReplacedBy[[]string]("label")),
+			cli.Flag(&opts.trace, "trace", flag.NoShortHand, "Trace internals", cli.DeprecatedFlag[bool]("it will be removed in v2")),
+			cli.Run(func(ctx context.Context, cmd *cli.Command) error { return nil }),
+		)
+		test.Ok(t, err)
+
+		return cmd
+	}
+
+	tests := []struct {
+		env    map[string]string // Environment variables to set
+		check  func(t *testing.T, opts options)
+		name   string   // Name of the test case
+		stderr string   // Expected output to stderr
+		args   []string // Arguments to execute with
+	}{
+		{
+			name: "not used",
+			args: []string{"--listen", ":9000"},
+			check: func(t *testing.T, opts options) {
+				test.Equal(t, opts.listen, ":9000")
+				test.Equal(t, opts.addr, "")
+			},
+		},
+		{
+			name:   "forwarded",
+			args:   []string{"-a", ":9000"},
+			stderr: "Warning: flag --addr is deprecated, use --listen instead\n",
+			check: func(t *testing.T, opts options) {
+				test.Equal(t, opts.listen, ":9000")
+				test.Equal(t, opts.addr, ":9000")
+			},
+		},
+		{
+			name:   "forwarded from env",
+			env:    map[string]string{"TEST_DEPRECATED_ADDR": ":7000"},
+			args:   []string{},
+			stderr: "Warning: flag --addr is deprecated, use --listen instead\n",
+			check: func(t *testing.T, opts options) {
+				test.Equal(t, opts.listen, ":7000")
+			},
+		},
+		{
+			name:   "replacement wins",
+			args:   []string{"--addr", ":9000", "--listen", ":9001"},
+			stderr: "Warning: flag --addr is deprecated, use --listen instead\n",
+			check: func(t *testing.T, opts options) {
+				test.Equal(t, opts.listen, ":9001")
+			},
+		},
+		{
+			name:   "slices forwarded element by element",
+			args:   []string{"--tag", "a", "--tag", "b"},
+			stderr: "Warning: flag --tag is deprecated, use --label instead\n",
+			check: func(t *testing.T, opts options) {
+				test.EqualFunc(t, opts.labels, []string{"a", "b"}, slices.Equal)
+			},
+		},
+		{
+			name:   "custom message",
+			args:   []string{"--trace"},
+			stderr: "Warning: flag --trace is deprecated, it will be removed in v2\n",
+			check: func(t *testing.T, opts options) {
+				test.True(t, opts.trace)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			var opts options
+
+			stderr := &bytes.Buffer{}
+
+			test.Ok(t, build(t, &opts, stderr, tt.args).Execute(t.Context()))
+			test.Equal(t, stderr.String(), tt.stderr)
+			tt.check(t, opts)
+		})
+	}
+}
+
+func TestFlagAlias(t *testing.T) {
+	tests := []struct {
+		name    string   // Name of the test case
+		colour  string   // Expected value of --colour
+		args    []string // Arguments to execute with
+		verbose bool     // Expected value of --verbose
+		ran     bool     // Whether the sub command should have run
+	}{
+		{
+			name:   "by name",
+			args:   []string{"--colour", "red"},
+			colour: "red",
+		},
+		{
+			name:   "by alias",
+			args:   []string{"--color", "blue"},
+			colour: "blue",
+		},
+		{
+			name:   "by alias with equals",
+			args:   []string{"--color=green"},
+			colour: "green",
+		},
+		{
+			name:    "bool alias before subcommand",
+			args:    []string{"--loud", "sub"},
+			verbose: true,
+			ran:     true,
+		},
+		{
+			name:   "value alias before subcommand",
+			args:   []string{"--color", "sub", "sub"},
+			colour: "sub",
+			ran:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				colour  string
+				verbose bool
+				ran     bool
+			)
+
+			// Subcommands parse their own flags so both need them, the root's are used
+			// to find the subcommand
+			flags := []cli.Option{
+				cli.Flag(&colour, "colour", 'c', "Output colour", cli.FlagAlias[string]("color")),
+				cli.Flag(&verbose, "verbose", 'v', "Say more", cli.FlagAlias[bool]("loud")),
+			}
+
+			sub := func() (*cli.Command, error) {
+				return cli.New(
+					"sub",
+					append(
+						flags,
+						cli.Run(func(ctx context.Context, cmd *cli.Command) error {
+							ran = true
+							return nil
+						}),
+					)...,
+				)
+			}
+
+			cmd, err := cli.New(
+				"test",
+				append(
+					flags,
+					cli.OverrideArgs(tt.args),
+					cli.SubCommands(sub),
+					cli.Run(func(ctx context.Context, cmd *cli.Command) error { return nil }),
+				)...,
+			)
+			test.Ok(t, err)
+
+			test.Ok(t, cmd.Execute(t.Context()))
+			test.Equal(t, colour, tt.colour)
+			test.Equal(t, verbose, tt.verbose)
+			test.Equal(t, ran, tt.ran)
+		})
+	}
+}
+
+func TestNoArgDefault(t *testing.T) {
+	tests := []struct {
+		name   string   // Name of the test case
+		colour string   // Expected value of --colour
+		args   []string // Arguments to execute with
+		want   []string // Expected positional arguments to the sub command
+	}{
+		{
+			name:   "not given",
+			args:   []string{"sub", "file.txt"},
+			colour: "auto",
+			want:   []string{"file.txt"},
+		},
+		{
+			name:   "bare before subcommand",
+			args:   []string{"--colour", "sub", "file.txt"},
+			colour: "always",
+			want:   []string{"file.txt"},
+		},
+		{
+			name:   "explicit before subcommand",
+			args:   []string{"--colour=never", "sub", "file.txt"},
+			colour: "never",
+			want:   []string{"file.txt"},
+		},
+		{
+			name:   "bare short before subcommand",
+			args:   []string{"-c", "sub"},
+			colour: "always",
+			want:   nil,
+		},
+		{
+			name:   "bare after subcommand",
+			args:   []string{"sub", "--colour", "file.txt"},
+			colour: "always",
+			want:   []string{"file.txt"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				colour string
+				got    []string
+			)
+
+			// Subcommands parse their own flags so both need it, the root's is used
+			// to find the subcommand
+			colourFlag := func() cli.Option {
+				return cli.Flag(&colour, "colour", 'c', "Colour the output", cli.FlagDefault[string]("auto"), cli.NoArgDefault[string]("always"))
+			}
+
+			sub := func() (*cli.Command, error) {
+				return cli.New(
+					"sub",
+					colourFlag(),
+					cli.Run(func(ctx context.Context, cmd *cli.Command) error {
+						got = cmd.Args()
+						return nil
+					}),
+				)
+			}
+
+			cmd, err := cli.New(
+				"test",
+				cli.OverrideArgs(tt.args),
+				colourFlag(),
+				cli.SubCommands(sub),
+				cli.Run(func(ctx context.Context, cmd *cli.Command) error { return nil }),
+			)
+			test.Ok(t, err)
+
+			test.Ok(t, cmd.Execute(t.Context()))
+			test.Equal(t, colour, tt.colour)
+			test.EqualFunc(t, got, tt.want, slices.Equal)
+		})
+	}
+}
+
+func TestCountFlag(t *testing.T) {
+	tests := []struct {
+		name      string     // Name of the test case
+		args      []string   // Arguments to execute with
+		want      []string   // Expected positional arguments to the sub command that ran
+		ran       string     // Name of the sub command that should have run
+		verbosity flag.Count // Expected value of --verbose
+	}{
+		{
+			name:      "value before subcommand",
+			args:      []string{"--verbose", "3", "sub", "file.txt"},
+			verbosity: 3,
+			ran:       "sub",
+			want:      []string{"file.txt"},
+		},
+		{
+			name:      "short value before subcommand",
+			args:      []string{"-v", "3", "sub"},
+			verbosity: 3,
+			ran:       "sub",
+			want:      nil,
+		},
+		{
+			name:      "shorthand group value before subcommand",
+			args:      []string{"-vv", "3", "sub", "file.txt"},
+			verbosity: 4, // The first v counts once, the last adds 3
+			ran:       "sub",
+			want:      []string{"file.txt"},
+		},
+		{
+			name:      "shorthand group before subcommand",
+			args:      []string{"-vv", "sub", "file.txt"},
+			verbosity: 2,
+			ran:       "sub",
+			want:      []string{"file.txt"},
+		},
+		{
+			name:      "no value before subcommand",
+			args:      []string{"-v", "sub", "file.txt"},
+			verbosity: 1,
+			ran:       "sub",
+			want:      []string{"file.txt"},
+		},
+		{
+			name:      "numeric subcommand not taken as value",
+			args:      []string{"--verbose", "2", "file.txt"},
+			verbosity: 1,
+			ran:       "2",
+			want:      []string{"file.txt"},
+		},
+		{
+			name:      "value after subcommand",
+			args:      []string{"sub", "--verbose", "3", "file.txt"},
+			verbosity: 3,
+			ran:       "sub",
+			want:      []string{"file.txt"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				verbosity flag.Count
+				ran       string
+				got       []string
+			)
+
+			// Subcommands parse their own flags so they need it too, the root's is
+			// used to find the subcommand
+			sub := func(name string) cli.Builder {
+				return func() (*cli.Command, error) {
+					return cli.New(
+						name,
+						cli.Flag(&verbosity, "verbose", 'v', "Say more"),
+						cli.Run(func(ctx context.Context, cmd *cli.Command) error {
+							ran = name
+							got = cmd.Args()
+
+							return nil
+						}),
+					)
+				}
+			}
+
+			cmd, err := cli.New(
+				"test",
+				cli.OverrideArgs(tt.args),
+				cli.Flag(&verbosity, "verbose", 'v', "Say more"),
+				cli.SubCommands(sub("sub"), sub("2")),
+			)
+			test.Ok(t, err)
+
+			test.Ok(t, cmd.Execute(t.Context()))
+			test.Equal(t, verbosity, tt.verbosity)
+			test.Equal(t, ran, tt.ran)
+			test.EqualFunc(t, got, tt.want, slices.Equal)
+		})
+	}
+}
+
+func TestReplaceDefault(t *testing.T) {
+	var items []string
+
+	cmd, err := cli.New(
+		"test",
+		cli.OverrideArgs([]string{"--items", "b,c"}),
+		cli.Flag(
+			&items,
+			"items",
+			'i',
+			"Items to process",
+			cli.FlagDefault([]string{"a"}),
+			cli.Delimiter[[]string](","),
+			cli.ReplaceDefault[[]string](),
+		),
+		cli.Run(func(ctx context.Context, cmd *cli.Command) error { return nil }),
+	)
+	test.Ok(t, err)
+
+	// Executing again must replace the default again, not add to the last run's values
+	for range 2 {
+		test.Ok(t, cmd.Execute(t.Context()))
+		test.EqualFunc(t, items, []string{"b", "c"}, slices.Equal)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "config.toml")
+	test.Ok(t, os.WriteFile(file, []byte("hello"), 0o644))
+
+	type options struct {
+		name    string
+		config  string
+		timeout time.Duration
+		port    int
+		workers int
+		tags    []string
+		dir     string
+	}
+
+	build := func(t *testing.T, opts *options, args []string) *cli.Command {
+		t.Helper()
+
+		cmd, err := cli.New(
+			"test",
+			cli.OverrideArgs(args),
+			cli.Stderr(io.Discard),
+			cli.Flag(&opts.port, "port", 'p', "Port", cli.FlagDefault(8080), cli.Validate(cli.Between(1, 65535))),
+			cli.Flag(&opts.timeout, "timeout", 't', "Timeout", cli.FlagDefault(time.Minute), cli.Validate(cli.Min(time.Second))),
+			cli.Flag(&opts.workers, "workers", 'w', "Workers", cli.Validate(cli.Min(1), cli.Max(8))),
+			cli.Flag(
+				&opts.name,
+				"name",
+				'n',
+				"Name",
+				cli.FlagDefault("default"),
+				cli.Env[string]("TEST_VALIDATE_NAME"),
+				cli.Validate(cli.NonEmpty, cli.Matches(regexp.MustCompile(`^[a-z]+$`))),
+			),
+			cli.Flag(&opts.config, "config", 'c', "Config file", cli.Validate(cli.FileExists)),
+			cli.Flag(&opts.tags, "tag", flag.NoShortHand, "Tags", cli.Validate(func(tags []string) error {
+				if len(tags) > 2 {
+					return errors.New("at most 2 tags")
+				}
+
+				return nil
+			})),
+			cli.Arg(&opts.dir, "dir", "Directory", cli.ArgDefault(dir), cli.ArgValidate(cli.DirExists)),
+			cli.Run(func(ctx context.Context, cmd *cli.Command) error { return nil }),
+		)
+		test.Ok(t, err)
+
+		return cmd
+	}
+
+	tests := []struct {
+		env     map[string]string // Environment variables to set
+		check   func(t *testing.T, opts options)
+		name    string   // Name of the test case
+		errMsg  string   // If we wanted an error, what should it say
+		args    []string // Arguments to execute with
+		wantErr bool     // Whether we want an error
+	}{
+		{
+			name: "all valid",
+			args: []string{"--port", "443", "--timeout", "5s", "-w", "8", "--name", "app", "--config", file, "--tag", "a", "--tag", "b", dir},
+			check: func(t *testing.T, opts options) {
+				test.Equal(t, opts.port, 443)
+				test.Equal(t, opts.timeout, 5*time.Second)
+				test.Equal(t, opts.workers, 8)
+				test.Equal(t, opts.name, "app")
+				test.Equal(t, opts.config, file)
+				test.EqualFunc(t, opts.tags, []string{"a", "b"}, slices.Equal)
+			},
+		},
+		{
+			name: "defaults are not validated",
+			args: []string{},
+			check: func(t *testing.T, opts options) {
+				test.Equal(t, opts.workers, 0)
+				test.Equal(t, opts.config, "")
+			},
+		},
+		{
+			name:    "out of range",
+			args:    []string{"--port", "0"},
+			wantErr: true,
+			errMsg:  `failed to parse command flags: parse error: flag "port" received invalid value "0" (expected int): must be between 1 and 65535`,
+		},
+		{
+			name:    "below minimum duration",
+			args:    []string{"--timeout", "10ms"},
+			wantErr: true,
+			errMsg:  `failed to parse command flags: parse error: flag "timeout" received invalid value "10ms" (expected time.Duration): must be at least 1s`,
+		},
+		{
+			name:    "above maximum",
+			args:    []string{"-w", "9"},
+			wantErr: true,
+			errMsg:  `failed to parse command flags: parse error: flag "workers" received invalid value "9" (expected int): must be at most 8`,
+		},
+		{
+			name:    "validators run in order",
+			args:    []string{"--name", ""},
+			wantErr: true,
+			errMsg:  `failed to parse command flags: parse error: flag "name" received invalid value "" (expected string): must not be empty`,
+		},
+		{
+			name:    "no match",
+			args:    []string{"--name", "App1"},
+			wantErr: true,
+			errMsg:  `failed to parse command flags: parse error: flag "name" received invalid value "App1" (expected string): must match ^[a-z]+$`,
+		},
+		{
+			name:    "env var validated",
+			env:     map[string]string{"TEST_VALIDATE_NAME": "NOPE"},
+			args:    []string{},
+			wantErr: true,
+			errMsg: "failed to parse command flags: could not set flag from env: env var TEST_VALIDATE_NAME: " +
+				`parse error: flag "name" received invalid value "NOPE" (expected string): must match ^[a-z]+$`,
+		},
+		{
+			name:    "missing file",
+			args:    []string{"--config", filepath.Join(dir, "missing.toml")},
+			wantErr: true,
+			errMsg: fmt.Sprintf(
+				`failed to parse command flags: parse error: flag "config" received invalid value %q (expected string): no such file or directory`,
+				filepath.Join(dir, "missing.toml"),
+			),
+		},
+		{
+			name:    "file is a directory",
+			args:    []string{"--config", dir},
+			wantErr: true,
+			errMsg:  fmt.Sprintf(`failed to parse command flags: parse error: flag "config" received invalid value %q (expected string): is a directory`, dir),
+		},
+		{
+			name:    "slice validated as a whole",
+			args:    []string{"--tag", "a", "--tag", "b", "--tag", "c"},
+			wantErr: true,
+			errMsg:  `failed to parse command flags: parse error: flag "tag" received invalid value "c" (expected []string): at most 2 tags`,
+		},
+		{
+			name:    "arg not a directory",
+			args:    []string{file},
+			wantErr: true,
+			errMsg: fmt.Sprintf(
+				`could not parse argument "dir" from provided input %q: parse error: argument "dir" received invalid value %q (expected string): not a directory`,
+				file,
+				file,
+			),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			var opts options
+
+			err := build(t, &opts, tt.args).Execute(t.Context())
+			test.WantErr(t, err, tt.wantErr)
+
+			if tt.wantErr {
+				test.Equal(t, err.Error(), tt.errMsg)
+				test.True(t, errors.Is(err, cli.ErrParse))
+
+				return
+			}
+
+			if tt.check != nil {
+				tt.check(t, opts)
+			}
+		})
+	}
+
+	t.Run("rejected value is not kept", func(t *testing.T) {
+		var port int
+
+		cmd, err := cli.New(
+			"test",
+			cli.OverrideArgs([]string{"--port", "70000"}),
+			cli.Flag(&port, "port", 'p', "Port", cli.FlagDefault(8080), cli.Validate(cli.Max(65535))),
+			cli.Run(func(ctx context.Context, cmd *cli.Command) error { return nil }),
+		)
+		test.Ok(t, err)
+
+		test.Err(t, cmd.Execute(t.Context()))
+		test.Equal(t, port, 8080)
+	})
+
+	t.Run("missing file matches fs.ErrNotExist", func(t *testing.T) {
+		test.True(t, errors.Is(cli.FileExists(filepath.Join(dir, "nope")), fs.ErrNotExist))
+		test.True(t, errors.Is(cli.DirExists(filepath.Join(dir, "nope")), fs.ErrNotExist))
+	})
+}
+
+func TestDescribe(t *testing.T) {
+	serve := func() (*cli.Command, error) {
+		return cli.New(
+			"serve",
+			cli.Short("Run the server"),
+			cli.Group("Server"),
+			cli.Flag(new(int), "port", 'p', "Port to listen on", cli.FlagDefault(8080), cli.Env[int]("TEST_PORT")),
+			cli.Flag(new([]string), "tag", flag.NoShortHand, "Tags to apply", cli.FlagDefault([]string{"a", "b"})),
+			cli.Flag(new([]string), "header", flag.NoShortHand, "Headers to send", cli.FlagDefault([]string{"Accept: a, b", "c"})),
+			cli.Flag(new(string), "token", flag.NoShortHand, "API token", cli.FlagDefault("secret"), cli.Sensitive[string](), cli.Required[string]()),
+			cli.Flag(new(string), "level", flag.NoShortHand, "Log level", cli.FlagDefault("info"), cli.Choices("debug", "info")),
+			cli.Flag(new(string), "colour", flag.NoShortHand, "Colour output", cli.FlagAlias[string]("color")),
+			cli.Arg(new(string), "dir", "Directory to serve", cli.ArgDefault(".")),
+			cli.Run(func(ctx context.Context, cmd *cli.Command) error { return nil }),
+		)
+	}
+
+	build := func(t *testing.T, options ...cli.Option) *cli.Command {
+		t.Helper()
+
+		cmd, err := cli.New(
+			"mytool",
+			slices.Concat(
+				[]cli.Option{
+					cli.Short("A tool for doing things"),
+					cli.Version("v1.2.3"),
+					cli.Example("Serve the current directory", "mytool serve ."),
+					cli.Shell(),
+					cli.SubCommands(cli.Lazy("serve", "Run the server", "Server", serve)),
+				},
+				options,
+			)...,
+		)
+		test.Ok(t, err)
+
+		return cmd
+	}
+
+	want := spec.Command{
+		Name:     "mytool",
+		Short:    "A tool for doing things",
+		Examples: []spec.Example{{Comment: "Serve the current directory", Command: "mytool serve ."}},
+		Commands: []spec.Command{
+			{
+				Name:  "serve",
+				Short: "Run the server",
+				Group: "Server",
+				Flags: []spec.Flag{
+					{Name: "colour", Aliases: []string{"color"}, Type: "string", Usage: "Colour output"},
+					{Name: "header", Type: "[]string", Usage: "Headers to send", Default: `"Accept: a, b",c`},
+					{Name: "level", Type: "string", Usage: "Log level", Default: "info", Choices: []string{"debug", "info"}},
+					{Name: "port", Short: "p", Type: "int", Usage: "Port to listen on", Default: "8080", Env: "TEST_PORT"},
+					{Name: "tag", Type: "[]string", Usage: "Tags to apply", Default: "a,b"},
+					{Name: "token", Type: "string", Usage: "API token", Required: true, Sensitive: true},
+				},
+				Args: []spec.Arg{{Name: "dir", Type: "string", Usage: "Directory to serve", Default: "."}},
+			},
+		},
+	}
+
+	equal := func(a, b spec.Command) bool { return reflect.DeepEqual(a, b) }
+
+	t.Run("describe", func(t *testing.T) {
+		got, err := build(t).Describe()
+		test.Ok(t, err)
+		test.EqualFunc(t, got, want, equal)
+	})
+
+	t.Run("env var", func(t *testing.T) {
+		name, version, _ := strings.Cut(cli.DescribeEnv, "=")
+		t.Setenv(name, version)
+
+		stdout := &bytes.Buffer{}
+		cmd := build(t, cli.Stdout(stdout), cli.OverrideArgs([]string{"serve", "--port", "not a number"}))
+		test.Ok(t, cmd.Execute(t.Context()))
+
+		var got spec.Command
+		test.Ok(t, json.Unmarshal(stdout.Bytes(), &got))
+		test.EqualFunc(t, got, want, equal)
+
+		// It's removed so it can't leak to anything the program runs
+		_, ok := os.LookupEnv(name)
+		test.False(t, ok)
+	})
+
+	t.Run("env var unknown version", func(t *testing.T) {
+		name, _, _ := strings.Cut(cli.DescribeEnv, "=")
+		t.Setenv(name, "99")
+
+		stdout := &bytes.Buffer{}
+		cmd := build(t, cli.Stdout(stdout), cli.OverrideArgs([]string{"serve"}))
+
+		err := cmd.Execute(t.Context())
+		test.Err(t, err)
+		test.Equal(t, err.Error(), `unsupported FOLLOWTHEPROCESS_CLI_DESCRIBE version "99", expected "1"`)
+		test.Equal(t, stdout.String(), "")
+	})
+
+	t.Run("lazy error", func(t *testing.T) {
+		broken := cli.Lazy("broken", "Fails to build", "", func() (*cli.Command, error) {
+			return nil, errors.New("oh no")
+		})
+
+		cmd, err := cli.New("test", cli.SubCommands(broken))
+		test.Ok(t, err)
+
+		_, err = cmd.Describe()
+		test.Err(t, err)
+		test.Equal(t, err.Error(), `could not build subcommand "broken": oh no`)
+	})
+}
+
+func TestTypedErrors(t *testing.T) {
+	sub := func() (*cli.Command, error) {
+		return cli.New(
+			"serve",
+			cli.Arg(new(int), "port", "Port to serve on"),
+			cli.Run(func(ctx context.Context, cmd *cli.Command) error { return nil }),
+		)
+	}
+
+	t.Run("unknown flag", func(t *testing.T) {
+		cmd, err := cli.New(
+			"test",
+			cli.SubCommands(sub),
+			cli.OverrideArgs([]string{"serve", "8080", "--nope"}),
+		)
+		test.Ok(t, err)
+
+		err = cmd.Execute(t.Context())
+		test.Err(t, err)
+
+		var unknown *cli.UnknownFlagError
+		test.True(t, errors.As(err, &unknown))
+		test.Equal(t, unknown.Name, "nope")
+		test.False(t, unknown.Shorthand)
+	})
+
+	t.Run("unknown short flag", func(t *testing.T) {
+		cmd, err := cli.New(
+			"test",
+			cli.SubCommands(sub),
+			cli.OverrideArgs([]string{"serve", "8080", "-x"}),
+		)
+		test.Ok(t, err)
+
+		err = cmd.Execute(t.Context())
+
+		var unknown *cli.UnknownFlagError
+		test.True(t, errors.As(err, &unknown))
+		test.Equal(t, unknown.Name, "x")
+		test.True(t, unknown.Shorthand)
+	})
+
+	t.Run("missing argument", func(t *testing.T) {
+		cmd, err := cli.New(
+			"test",
+			cli.SubCommands(sub),
+			cli.OverrideArgs([]string{"serve"}),
+		)
+		test.Ok(t, err)
+
+		err = cmd.Execute(t.Context())
+
+		var missing *cli.MissingArgumentError
+		test.True(t, errors.As(err, &missing))
+		test.Equal(t, missing.Name, "port")
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		cmd, err := cli.New(
+			"test",
+			cli.SubCommands(sub),
+			cli.OverrideArgs([]string{"serve", "notanumber"}),
+		)
+		test.Ok(t, err)
+
+		err = cmd.Execute(t.Context())
+		test.True(t, errors.Is(err, cli.ErrParse))
+		test.True(t, errors.Is(err, strconv.ErrSyntax))
+
+		var invalid *cli.InvalidValueError
+		test.True(t, errors.As(err, &invalid))
+		test.Equal(t, invalid.Name, "port")
+		test.Equal(t, invalid.Value, "notanumber")
+		test.Equal(t, invalid.Kind, "argument")
+		test.Equal(t, invalid.Type, "int")
+	})
+
+	t.Run("unknown command", func(t *testing.T) {
+		cmd, err := cli.New(
+			"test",
+			cli.SubCommands(sub),
+			cli.Stderr(io.Discard),
+			cli.OverrideArgs([]string{"sevre", "8080"}),
+		)
+		test.Ok(t, err)
+
+		err = cmd.Execute(t.Context())
+
+		var unknown *cli.UnknownCommandError
+		test.True(t, errors.As(err, &unknown))
+		test.Equal(t, unknown.Name, "sevre")
+		test.Equal(t, unknown.Parent, "test")
+		test.EqualFunc(t, unknown.Suggestions, []string{"serve"}, slices.Equal)
+		test.Equal(t, err.Error(), "unknown command \"sevre\" for \"test\"\n\nDid you mean this?\n\tserve\n\nRun 'test --help' for usage.")
+	})
+}
+
+// The order in which we apply options shouldn't matter, this test
+// shuffles the order of the options and asserts the Command we get
+// out behaves the same as a baseline.
+func TestCommandOptionOrder(t *testing.T) {
+	baseLineStdout := &bytes.Buffer{}
+	baseLineStderr := &bytes.Buffer{}
+
+	shuffleStdout := &bytes.Buffer{}
+	shuffleStderr := &bytes.Buffer{}
+
+	var (
+		f     bool
+		count int
+	)
+
+	sub := func() (*cli.Command, error) {
+		return cli.New(
+			"sub",
+			cli.Run(func(ctx context.Context, cmd *cli.Command) error {
+				fmt.Fprintln(cmd.Stdout(), "Hello from sub")
+
+				return nil
+			}),
+		)
+	}
+
+	options := []cli.Option{
 		cli.OverrideArgs([]string{"some", "args", "here", "--flag", "--count", "10"}),
 		cli.Short("Short description"),
 		cli.Long("Long description"),
@@ -1187,6 +3725,56 @@ func BenchmarkNew(b *testing.B) {
 	}
 }
 
+// BenchmarkNewManySubcommands compares building a CLI with lots of subcommands
+// eagerly against building it with lazy subcommands.
+func BenchmarkNewManySubcommands(b *testing.B) {
+	const n = 100
+
+	builder := func(name string) cli.Builder {
+		return func() (*cli.Command, error) {
+			return cli.New(
+				name,
+				cli.Short("A subcommand"),
+				cli.Flag(new(bool), "force", 'f', "Force something"),
+				cli.Flag(new(string), "name", 'n', "The name of something"),
+				cli.Flag(new(int), "count", 'c', "Count something", cli.FlagDefault(1)),
+				cli.Run(func(ctx context.Context, cmd *cli.Command) error { return nil }),
+			)
+		}
+	}
+
+	eager := make([]cli.Builder, 0, n)
+	lazy := make([]cli.Builder, 0, n)
+
+	for i := range n {
+		name := "sub" + strconv.Itoa(i)
+		eager = append(eager, builder(name))
+		lazy = append(lazy, cli.Lazy(name, "A subcommand", "", builder(name)))
+	}
+
+	b.Run("eager", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for b.Loop() {
+			_, err := cli.New("benchy", cli.SubCommands(eager...))
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("lazy", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for b.Loop() {
+			_, err := cli.New("benchy", cli.SubCommands(lazy...))
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
 // BenchmarkExecute measures the performance of actually invoking a CLI.
 func BenchmarkExecute(b *testing.B) {
 	var (
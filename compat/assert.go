@@ -0,0 +1,117 @@
+package compat
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.followtheprocess.codes/cli"
+	"go.followtheprocess.codes/cli/spec"
+	"go.yaml.in/yaml/v4"
+)
+
+// Option is a functional option for configuring [Assert].
+type Option func(cfg *config)
+
+// config holds the configuration for Assert.
+type config struct {
+	update bool
+}
+
+// Update is an [Option] that, when update is true, makes [Assert] write the current
+// command tree to the baseline file instead of checking against it. It's intended to be
+// hooked up to a test flag, in the same way as snapshot tests:
+//
+//	var update = flag.Bool("update", false, "Update the CLI baseline")
+func Update(update bool) Option {
+	return func(cfg *config) {
+		cfg.update = update
+	}
+}
+
+// Load reads a [spec.Command] from the JSON or YAML file at path, such as one written by
+// [Assert] or 'cli-gen describe'.
+func Load(path string) (spec.Command, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return spec.Command{}, fmt.Errorf("could not read spec: %w", err)
+	}
+
+	// YAML is a superset of JSON so this handles both
+	var root spec.Command
+	if err := yaml.Unmarshal(contents, &root); err != nil {
+		return spec.Command{}, fmt.Errorf("could not parse spec %s: %w", path, err)
+	}
+
+	return root, nil
+}
+
+// Assert fails the test if the command tree built by builder has any breaking changes
+// compared to the baseline spec at path, listing every change found.
+//
+// If the baseline file doesn't exist yet, or the [Update] option is set, the current
+// command tree is written to it as JSON instead and the test passes.
+func Assert(tb testing.TB, baseline string, builder cli.Builder, options ...Option) {
+	tb.Helper()
+
+	cfg := config{}
+	for _, option := range options {
+		option(&cfg)
+	}
+
+	cmd, err := builder()
+	if err != nil {
+		tb.Fatalf("could not build command: %v", err)
+	}
+
+	current, err := cmd.Describe()
+	if err != nil {
+		tb.Fatalf("could not describe command: %v", err)
+	}
+
+	_, err = os.Stat(baseline)
+	if cfg.update || os.IsNotExist(err) {
+		if err := writeBaseline(baseline, current); err != nil {
+			tb.Fatalf("could not write baseline: %v", err)
+		}
+
+		return
+	}
+
+	old, err := Load(baseline)
+	if err != nil {
+		tb.Fatalf("could not load baseline: %v", err)
+	}
+
+	changes := Check(old, current)
+	if len(changes) == 0 {
+		return
+	}
+
+	s := &strings.Builder{}
+	fmt.Fprintf(s, "%d breaking change(s) to the command line interface since %s:\n", len(changes), baseline)
+
+	for _, change := range changes {
+		fmt.Fprintf(s, "\t- %s\n", change)
+	}
+
+	s.WriteString("\nIf these are intentional, update the baseline")
+	tb.Error(s.String())
+}
+
+// writeBaseline writes root to path as indented JSON, creating any parent directories.
+func writeBaseline(path string, root spec.Command) error {
+	out, err := json.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil { //nolint:gosec // Same as the snapshot library, testdata is not secret
+		return err
+	}
+
+	return os.WriteFile(path, append(out, '\n'), 0o644) //nolint:gosec // Same as the snapshot library, testdata is not secret
+}
@@ -0,0 +1,290 @@
+// Package compat finds the changes between two versions of a command line interface
+// that would break the scripts of the people using it: removed commands and flags,
+// changed types, new required arguments and so on.
+//
+// Interfaces are compared as [spec.Command] descriptions, typically a baseline checked in
+// to the repo and the current command tree, so a breaking change fails a test rather
+// than a user's script:
+//
+//	func TestCompatibility(t *testing.T) {
+//		compat.Assert(t, "testdata/cli.json", BuildRoot, compat.Update(*update))
+//	}
+//
+// Intentional changes are accepted by updating the baseline.
+package compat // import "go.followtheprocess.codes/cli/compat"
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"go.followtheprocess.codes/cli/spec"
+)
+
+// Kind is the kind of breaking change.
+type Kind int
+
+const (
+	// CommandRemoved is a subcommand that no longer exists.
+	CommandRemoved Kind = iota
+
+	// FlagRemoved is a flag that no longer exists.
+	FlagRemoved
+
+	// ShorthandRemoved is a flag whose shorthand was removed or changed to another character.
+	ShorthandRemoved
+
+	// TypeChanged is a flag or argument whose type changed, so values that used to
+	// parse may not any more.
+	TypeChanged
+
+	// EnvChanged is a flag whose environment variable was removed or renamed.
+	EnvChanged
+
+	// DefaultChanged is a flag or argument whose default value changed.
+	DefaultChanged
+
+	// FlagRequired is a flag that is now required.
+	FlagRequired
+
+	// ChoiceRemoved is a flag or argument that no longer accepts a value it used to.
+	ChoiceRemoved
+
+	// ArgRemoved is a positional argument that no longer exists.
+	ArgRemoved
+
+	// ArgRequired is a positional argument that is newly required, either because it's new
+	// or because it lost its default.
+	ArgRequired
+)
+
+// String implements [fmt.Stringer] for Kind.
+func (k Kind) String() string {
+	switch k {
+	case CommandRemoved:
+		return "command removed"
+	case FlagRemoved:
+		return "flag removed"
+	case ShorthandRemoved:
+		return "shorthand removed"
+	case TypeChanged:
+		return "type changed"
+	case EnvChanged:
+		return "env var changed"
+	case DefaultChanged:
+		return "default changed"
+	case FlagRequired:
+		return "flag required"
+	case ChoiceRemoved:
+		return "choice removed"
+	case ArgRemoved:
+		return "argument removed"
+	case ArgRequired:
+		return "argument required"
+	default:
+		return fmt.Sprintf("Kind(%d)", int(k))
+	}
+}
+
+// Change is a single breaking change between two versions of an interface.
+type Change struct {
+	// Command is the full path of the command the change is in e.g. "mytool serve".
+	Command string
+
+	// Detail describes the change e.g. `flag --port type changed from int to string`.
+	Detail string
+
+	// Kind is the kind of change.
+	Kind Kind
+}
+
+// String implements [fmt.Stringer] for Change.
+func (c Change) String() string {
+	return c.Command + ": " + c.Detail
+}
+
+// Check returns the breaking changes from old to updated, in the order they appear in old,
+// or nil if there are none.
+//
+// Anything added to updated is fine, apart from arguments and flags that must be
+// provided. Flags are matched by name or alias, so renaming a flag while keeping the
+// old name as an alias is not a breaking change. Positional arguments are compared by
+// position as their names are never typed on the command line, and changes to the
+// defaults of [spec.Flag.Sensitive] flags are ignored as they aren't shown anywhere.
+func Check(old, updated spec.Command) []Change {
+	c := &checker{}
+	c.command(old, updated, old.Name)
+
+	return c.changes
+}
+
+// checker accumulates the changes found while walking two command trees.
+type checker struct {
+	changes []Change
+}
+
+// add records a change in the command at path.
+func (c *checker) add(kind Kind, path, format string, args ...any) {
+	c.changes = append(c.changes, Change{
+		Command: path,
+		Detail:  fmt.Sprintf(format, args...),
+		Kind:    kind,
+	})
+}
+
+// command checks old against updated, which are both the command at path, and recursively
+// all their subcommands.
+func (c *checker) command(old, updated spec.Command, path string) {
+	for _, oldFlag := range old.Flags {
+		index := slices.IndexFunc(updated.Flags, func(f spec.Flag) bool { return hasName(f, oldFlag.Name) })
+		if index == -1 {
+			c.add(FlagRemoved, path, "flag --%s was removed", oldFlag.Name)
+			continue
+		}
+
+		c.flag(oldFlag, updated.Flags[index], path)
+	}
+
+	for _, newFlag := range updated.Flags {
+		if newFlag.Required && !slices.ContainsFunc(old.Flags, func(f spec.Flag) bool { return hasName(f, newFlag.Name) }) {
+			c.add(FlagRequired, path, "new flag --%s is required", newFlag.Name)
+		}
+	}
+
+	for i, oldArg := range old.Args {
+		if i >= len(updated.Args) {
+			c.add(ArgRemoved, path, "argument %d (%s) was removed", i+1, oldArg.Name)
+			continue
+		}
+
+		c.arg(oldArg, updated.Args[i], i, path)
+	}
+
+	for i := len(old.Args); i < len(updated.Args); i++ {
+		if updated.Args[i].Default == "" {
+			c.add(ArgRequired, path, "new argument %d (%s) is required", i+1, updated.Args[i].Name)
+		}
+	}
+
+	for _, oldSub := range old.Commands {
+		index := slices.IndexFunc(updated.Commands, func(cmd spec.Command) bool { return cmd.Name == oldSub.Name })
+		if index == -1 {
+			c.add(CommandRemoved, path, "subcommand %q was removed", oldSub.Name)
+			continue
+		}
+
+		c.command(oldSub, updated.Commands[index], path+" "+oldSub.Name)
+	}
+}
+
+// flag checks the flag old against its new version.
+func (c *checker) flag(old, updated spec.Flag, path string) {
+	name := "--" + old.Name
+
+	for _, alias := range old.Aliases {
+		if !hasName(updated, alias) {
+			c.add(FlagRemoved, path, "flag %s alias --%s was removed", name, alias)
+		}
+	}
+
+	if old.Short != "" && updated.Short != old.Short {
+		if updated.Short == "" {
+			c.add(ShorthandRemoved, path, "flag %s shorthand -%s was removed", name, old.Short)
+		} else {
+			c.add(ShorthandRemoved, path, "flag %s shorthand changed from -%s to -%s", name, old.Short, updated.Short)
+		}
+	}
+
+	if updated.Type != old.Type {
+		c.add(TypeChanged, path, "flag %s type changed from %s to %s", name, old.Type, updated.Type)
+	}
+
+	// Values that used to be one item may now be split, or vice versa
+	if updated.Delimiter != old.Delimiter {
+		c.add(TypeChanged, path, "flag %s delimiter changed from %s to %s", name, quote(old.Delimiter), quote(updated.Delimiter))
+	}
+
+	if old.Env != "" && updated.Env != old.Env {
+		if updated.Env == "" {
+			c.add(EnvChanged, path, "flag %s env var $%s was removed", name, old.Env)
+		} else {
+			c.add(EnvChanged, path, "flag %s env var changed from $%s to $%s", name, old.Env, updated.Env)
+		}
+	}
+
+	if updated.Default != old.Default && !old.Sensitive && !updated.Sensitive {
+		c.add(DefaultChanged, path, "flag %s default changed from %s to %s", name, quote(old.Default), quote(updated.Default))
+	}
+
+	// Either way round this changes what "--flag value" means, with a no-arg default
+	// the value is a positional argument rather than the flag's
+	if updated.NoArgDefault != old.NoArgDefault {
+		c.add(DefaultChanged, path, "flag %s no-arg default changed from %s to %s", name, quote(old.NoArgDefault), quote(updated.NoArgDefault))
+	}
+
+	// Only matters if there's a default to add to or replace
+	if updated.ReplaceDefault != old.ReplaceDefault && (old.Default != "" || updated.Default != "") {
+		if updated.ReplaceDefault {
+			c.add(DefaultChanged, path, "flag %s values now replace its default", name)
+		} else {
+			c.add(DefaultChanged, path, "flag %s values are now added to its default", name)
+		}
+	}
+
+	if updated.Required && !old.Required {
+		c.add(FlagRequired, path, "flag %s is now required", name)
+	}
+
+	c.choices(old.Choices, updated.Choices, "flag "+name, path)
+}
+
+// arg checks the argument old, at the given index, against its new version.
+func (c *checker) arg(old, updated spec.Arg, index int, path string) {
+	name := fmt.Sprintf("argument %d (%s)", index+1, old.Name)
+
+	if updated.Type != old.Type {
+		c.add(TypeChanged, path, "%s type changed from %s to %s", name, old.Type, updated.Type)
+	}
+
+	switch {
+	case old.Default != "" && updated.Default == "":
+		c.add(ArgRequired, path, "%s is now required, it had default %s", name, quote(old.Default))
+	case updated.Default != old.Default && old.Default != "":
+		c.add(DefaultChanged, path, "%s default changed from %s to %s", name, quote(old.Default), quote(updated.Default))
+	}
+
+	c.choices(old.Choices, updated.Choices, name, path)
+}
+
+// choices checks that updated still allows everything old did, no choices meaning
+// any value is allowed.
+func (c *checker) choices(old, updated []string, name, path string) {
+	if len(updated) == 0 {
+		return
+	}
+
+	if len(old) == 0 {
+		c.add(ChoiceRemoved, path, "%s is now restricted to %s", name, strings.Join(updated, ", "))
+		return
+	}
+
+	for _, choice := range old {
+		if !slices.Contains(updated, choice) {
+			c.add(ChoiceRemoved, path, "%s no longer accepts %q", name, choice)
+		}
+	}
+}
+
+// hasName reports whether f may be given as --name, either by its name or an alias.
+func hasName(f spec.Flag, name string) bool {
+	return f.Name == name || slices.Contains(f.Aliases, name)
+}
+
+// quote formats a default value for a change detail, making the empty default visible.
+func quote(value string) string {
+	if value == "" {
+		return "none"
+	}
+
+	return fmt.Sprintf("%q", value)
+}
@@ -0,0 +1,346 @@
+package compat_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.followtheprocess.codes/cli"
+	"go.followtheprocess.codes/cli/compat"
+	"go.followtheprocess.codes/cli/spec"
+	"go.followtheprocess.codes/test"
+)
+
+func TestCheck(t *testing.T) {
+	base := func() spec.Command {
+		return spec.Command{
+			Name: "mytool",
+			Commands: []spec.Command{
+				{
+					Name: "serve",
+					Flags: []spec.Flag{
+						{Name: "port", Short: "p", Type: "int", Default: "8080", Env: "MYTOOL_PORT"},
+						{Name: "level", Aliases: []string{"log-level"}, Type: "string", Default: "info", Choices: []string{"debug", "info"}},
+						{Name: "token", Type: "string", Default: "secret", Sensitive: true},
+					},
+					Args: []spec.Arg{
+						{Name: "dir", Type: "string", Default: "."},
+					},
+				},
+				{Name: "version"},
+			},
+		}
+	}
+
+	tests := []struct {
+		modify func(cmd *spec.Command) // Change to make to the base spec
+		name   string                  // Name of the test case
+		want   []string                // Expected changes, as strings
+	}{
+		{
+			name:   "identical",
+			modify: func(cmd *spec.Command) {},
+			want:   nil,
+		},
+		{
+			name: "additions",
+			modify: func(cmd *spec.Command) {
+				cmd.Flags = append(cmd.Flags, spec.Flag{Name: "debug", Type: "bool"})
+				cmd.Commands = append(cmd.Commands, spec.Command{Name: "new"})
+				cmd.Commands[0].Args = append(cmd.Commands[0].Args, spec.Arg{Name: "extra", Type: "string", Default: "x"})
+				cmd.Commands[0].Flags[1].Choices = append(cmd.Commands[0].Flags[1].Choices, "warn")
+			},
+			want: nil,
+		},
+		{
+			name: "command removed",
+			modify: func(cmd *spec.Command) {
+				cmd.Commands = cmd.Commands[:1]
+			},
+			want: []string{`mytool: subcommand "version" was removed`},
+		},
+		{
+			name: "flag removed",
+			modify: func(cmd *spec.Command) {
+				cmd.Commands[0].Flags = cmd.Commands[0].Flags[1:]
+			},
+			want: []string{"mytool serve: flag --port was removed"},
+		},
+		{
+			name: "flag renamed",
+			modify: func(cmd *spec.Command) {
+				cmd.Commands[0].Flags[0].Name = "listen"
+			},
+			want: []string{"mytool serve: flag --port was removed"},
+		},
+		{
+			name: "flag renamed keeping alias",
+			modify: func(cmd *spec.Command) {
+				cmd.Commands[0].Flags[0].Name = "listen-port"
+				cmd.Commands[0].Flags[0].Aliases = []string{"port"}
+			},
+			want: nil,
+		},
+		{
+			name: "alias removed",
+			modify: func(cmd *spec.Command) {
+				cmd.Commands[0].Flags[1].Aliases = nil
+			},
+			want: []string{"mytool serve: flag --level alias --log-level was removed"},
+		},
+		{
+			name: "shorthand removed",
+			modify: func(cmd *spec.Command) {
+				cmd.Commands[0].Flags[0].Short = ""
+			},
+			want: []string{"mytool serve: flag --port shorthand -p was removed"},
+		},
+		{
+			name: "shorthand changed",
+			modify: func(cmd *spec.Command) {
+				cmd.Commands[0].Flags[0].Short = "P"
+			},
+			want: []string{"mytool serve: flag --port shorthand changed from -p to -P"},
+		},
+		{
+			name: "flag type changed",
+			modify: func(cmd *spec.Command) {
+				cmd.Commands[0].Flags[0].Type = "string"
+			},
+			want: []string{"mytool serve: flag --port type changed from int to string"},
+		},
+		{
+			name: "delimiter added",
+			modify: func(cmd *spec.Command) {
+				cmd.Commands[0].Flags[0].Delimiter = ","
+			},
+			want: []string{`mytool serve: flag --port delimiter changed from none to ","`},
+		},
+		{
+			name: "replace default",
+			modify: func(cmd *spec.Command) {
+				cmd.Commands[0].Flags[0].ReplaceDefault = true
+			},
+			want: []string{"mytool serve: flag --port values now replace its default"},
+		},
+		{
+			name: "env var renamed",
+			modify: func(cmd *spec.Command) {
+				cmd.Commands[0].Flags[0].Env = "PORT"
+			},
+			want: []string{"mytool serve: flag --port env var changed from $MYTOOL_PORT to $PORT"},
+		},
+		{
+			name: "env var removed",
+			modify: func(cmd *spec.Command) {
+				cmd.Commands[0].Flags[0].Env = ""
+			},
+			want: []string{"mytool serve: flag --port env var $MYTOOL_PORT was removed"},
+		},
+		{
+			name: "default changed",
+			modify: func(cmd *spec.Command) {
+				cmd.Commands[0].Flags[0].Default = "9000"
+				cmd.Commands[0].Flags[1].Default = ""
+			},
+			want: []string{
+				`mytool serve: flag --port default changed from "8080" to "9000"`,
+				`mytool serve: flag --level default changed from "info" to none`,
+			},
+		},
+		{
+			name: "no-arg default changed",
+			modify: func(cmd *spec.Command) {
+				cmd.Commands[0].Flags[0].NoArgDefault = "80"
+			},
+			want: []string{`mytool serve: flag --port no-arg default changed from none to "80"`},
+		},
+		{
+			name: "sensitive default changed",
+			modify: func(cmd *spec.Command) {
+				cmd.Commands[0].Flags[2].Default = ""
+			},
+			want: nil,
+		},
+		{
+			name: "flag now required",
+			modify: func(cmd *spec.Command) {
+				cmd.Commands[0].Flags[0].Required = true
+				cmd.Flags = append(cmd.Flags, spec.Flag{Name: "config", Type: "string", Required: true})
+			},
+			want: []string{
+				"mytool: new flag --config is required",
+				"mytool serve: flag --port is now required",
+			},
+		},
+		{
+			name: "choice removed",
+			modify: func(cmd *spec.Command) {
+				cmd.Commands[0].Flags[1].Choices = []string{"info"}
+			},
+			want: []string{`mytool serve: flag --level no longer accepts "debug"`},
+		},
+		{
+			name: "choices added",
+			modify: func(cmd *spec.Command) {
+				cmd.Commands[0].Args[0].Choices = []string{".", "/srv"}
+			},
+			want: []string{"mytool serve: argument 1 (dir) is now restricted to ., /srv"},
+		},
+		{
+			name: "arg removed",
+			modify: func(cmd *spec.Command) {
+				cmd.Commands[0].Args = nil
+			},
+			want: []string{"mytool serve: argument 1 (dir) was removed"},
+		},
+		{
+			name: "arg renamed",
+			modify: func(cmd *spec.Command) {
+				cmd.Commands[0].Args[0].Name = "directory"
+			},
+			want: nil,
+		},
+		{
+			name: "arg type changed",
+			modify: func(cmd *spec.Command) {
+				cmd.Commands[0].Args[0].Type = "int"
+				cmd.Commands[0].Args[0].Default = "1"
+			},
+			want: []string{
+				"mytool serve: argument 1 (dir) type changed from string to int",
+				`mytool serve: argument 1 (dir) default changed from "." to "1"`,
+			},
+		},
+		{
+			name: "arg lost default",
+			modify: func(cmd *spec.Command) {
+				cmd.Commands[0].Args[0].Default = ""
+			},
+			want: []string{`mytool serve: argument 1 (dir) is now required, it had default "."`},
+		},
+		{
+			name: "new required arg",
+			modify: func(cmd *spec.Command) {
+				cmd.Commands[0].Args = append(cmd.Commands[0].Args, spec.Arg{Name: "host", Type: "string"})
+			},
+			want: []string{"mytool serve: new argument 2 (host) is required"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updated := base()
+			tt.modify(&updated)
+
+			var got []string
+			for _, change := range compat.Check(base(), updated) {
+				got = append(got, change.String())
+			}
+
+			test.EqualFunc(t, got, tt.want, func(a, b []string) bool {
+				return strings.Join(a, "\n") == strings.Join(b, "\n")
+			})
+		})
+	}
+}
+
+func TestKind(t *testing.T) {
+	test.Equal(t, compat.FlagRemoved.String(), "flag removed")
+	test.Equal(t, compat.ArgRequired.String(), "argument required")
+	test.Equal(t, compat.Kind(100).String(), "Kind(100)")
+}
+
+// fakeTB records failures rather than failing the real test.
+type fakeTB struct {
+	testing.TB
+
+	failures []string
+}
+
+func (f *fakeTB) Helper() {}
+
+func (f *fakeTB) Error(args ...any) {
+	f.failures = append(f.failures, fmt.Sprint(args...))
+}
+
+func (f *fakeTB) Fatalf(format string, args ...any) {
+	f.failures = append(f.failures, fmt.Sprintf(format, args...))
+}
+
+func TestAssert(t *testing.T) {
+	builder := func(port string) cli.Builder {
+		return func() (*cli.Command, error) {
+			var value int
+
+			return cli.New(
+				"mytool",
+				cli.Flag(&value, port, 'p', "Port to listen on"),
+				cli.Run(func(ctx context.Context, cmd *cli.Command) error { return nil }),
+			)
+		}
+	}
+
+	baseline := filepath.Join(t.TempDir(), "testdata", "cli.json")
+
+	// First run writes the baseline
+	tb := &fakeTB{TB: t}
+	compat.Assert(tb, baseline, builder("port"))
+	test.Equal(t, len(tb.failures), 0)
+
+	_, err := os.Stat(baseline)
+	test.Ok(t, err)
+
+	// Unchanged interface passes
+	tb = &fakeTB{TB: t}
+	compat.Assert(tb, baseline, builder("port"))
+	test.Equal(t, len(tb.failures), 0)
+
+	// Breaking change fails, listing the change
+	tb = &fakeTB{TB: t}
+	compat.Assert(tb, baseline, builder("listen"))
+	test.Equal(t, len(tb.failures), 1)
+	test.True(t, strings.Contains(tb.failures[0], "mytool: flag --port was removed"))
+
+	// Updating accepts the change
+	tb = &fakeTB{TB: t}
+	compat.Assert(tb, baseline, builder("listen"), compat.Update(true))
+	test.Equal(t, len(tb.failures), 0)
+
+	loaded, err := compat.Load(baseline)
+	test.Ok(t, err)
+	test.Equal(t, loaded.Flags[0].Name, "listen")
+}
+
+func TestCheckDescribedAliases(t *testing.T) {
+	describe := func(t *testing.T, name string, options ...cli.FlagOption[string]) spec.Command {
+		t.Helper()
+
+		cmd, err := cli.New(
+			"mytool",
+			cli.Flag(new(string), name, 'a', "Address to listen on", options...),
+			cli.Run(func(ctx context.Context, cmd *cli.Command) error { return nil }),
+		)
+		test.Ok(t, err)
+
+		description, err := cmd.Describe()
+		test.Ok(t, err)
+
+		return description
+	}
+
+	original := describe(t, "addr")
+	renamed := describe(t, "listen", cli.FlagAlias[string]("addr"))
+
+	// Renaming a flag but keeping the old name as an alias is fine
+	test.Equal(t, len(compat.Check(original, renamed)), 0)
+
+	// Dropping the alias later is not
+	changes := compat.Check(renamed, describe(t, "listen"))
+	test.Equal(t, len(changes), 1)
+	test.Equal(t, changes[0].Kind, compat.FlagRemoved)
+	test.True(t, strings.Contains(changes[0].String(), "alias --addr was removed"))
+}
@@ -0,0 +1,191 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	publicflag "go.followtheprocess.codes/cli/flag"
+	"go.followtheprocess.codes/cli/internal/flag"
+	"go.followtheprocess.codes/cli/internal/parse"
+	"go.followtheprocess.codes/cli/spec"
+)
+
+// DescribeEnv is the environment variable, as a "NAME=VALUE" entry, that makes
+// [Command.Execute] write the spec of the command tree to stdout as JSON rather than
+// running anything. It's how 'cli-gen describe' gets the spec of a program built with cli.
+//
+// The value is the version of the describe protocol, a program asked for a version it
+// doesn't know returns an error rather than running. The variable is removed from the
+// environment before anything else happens so it's never inherited by other programs.
+const DescribeEnv = describeEnvVar + "=" + describeVersion
+
+const (
+	describeEnvVar  = "FOLLOWTHEPROCESS_CLI_DESCRIBE"
+	describeVersion = "1"
+)
+
+// Describe returns the declarative [spec.Command] describing cmd and all of its
+// subcommands, e.g. so the interface can be reviewed or diffed without reading Go.
+//
+// The built in flags (--help, --version and --no-input) and subcommands (help, shell
+// and plugins) are left out, as are the defaults of [Sensitive] flags. Any [Lazy]
+// subcommands are built, and an error is returned if one fails.
+//
+// Setting the [DescribeEnv] environment variable makes [Command.Execute] write the root
+// command's spec to stdout as JSON instead of running anything, this is what the cli-gen
+// tool's describe command uses to read the spec of an existing program.
+func (cmd *Command) Describe() (spec.Command, error) {
+	cmd, err := cmd.resolve()
+	if err != nil {
+		return spec.Command{}, err
+	}
+
+	description := spec.Command{
+		Name:  cmd.name,
+		Long:  cmd.long,
+		Group: cmd.group,
+	}
+
+	if cmd.short != defaultShort {
+		description.Short = cmd.short
+	}
+
+	for _, example := range cmd.examples {
+		description.Examples = append(description.Examples, spec.Example{
+			Comment: example.comment,
+			Command: example.command,
+		})
+	}
+
+	for name, fl := range cmd.flagSet().Sorted() {
+		if name == "help" || name == "version" || (name == noInputFlag && hasPrompts(cmd)) {
+			continue
+		}
+
+		description.Flags = append(description.Flags, describeFlag(fl))
+	}
+
+	for _, argument := range cmd.args {
+		description.Args = append(description.Args, spec.Arg{
+			Name:    argument.Name(),
+			Type:    argument.Type(),
+			Usage:   argument.Usage(),
+			Default: argument.Default(),
+			Choices: argument.Choices(),
+		})
+	}
+
+	for _, subcommand := range cmd.subcommands {
+		if subcommand.builtin {
+			continue
+		}
+
+		sub, err := subcommand.Describe()
+		if err != nil {
+			return spec.Command{}, err
+		}
+
+		description.Commands = append(description.Commands, sub)
+	}
+
+	return description, nil
+}
+
+// describeFlag returns the [spec.Flag] describing fl.
+func describeFlag(fl flag.Value) spec.Flag {
+	description := spec.Flag{
+		Name:           fl.Name(),
+		Aliases:        fl.Aliases(),
+		Type:           fl.Type(),
+		NoArgDefault:   fl.NoArgDefault(),
+		Delimiter:      fl.Delimiter(),
+		ReplaceDefault: fl.ReplaceDefault(),
+		Usage:          fl.Usage(),
+		Env:            fl.EnvVar(),
+		Group:          fl.Group(),
+		Choices:        fl.Choices(),
+		Required:       fl.Required(),
+		Sensitive:      fl.Sensitive(),
+		Deprecated:     fl.Deprecated(),
+		ReplacedBy:     fl.ReplacedBy(),
+		Hidden:         fl.Hidden(),
+	}
+
+	if fl.Short() != publicflag.NoShortHand {
+		description.Short = string(fl.Short())
+	}
+
+	if !fl.Sensitive() {
+		description.Default = fl.Default()
+		if fl.IsSlice() {
+			description.Default = commaSeparated(description.Default)
+		}
+	}
+
+	return description
+}
+
+// commaSeparated converts the help text format of a slice e.g. `["a", "b"]` to the
+// comma separated form used in a spec (and environment variables) e.g. "a,b".
+//
+// String items are quoted in the help text so may contain ", " themselves, they're
+// re-quoted as needed (see [parse.Join]) so they're split back out the same.
+func commaSeparated(slice string) string {
+	rest := strings.TrimSuffix(strings.TrimPrefix(slice, "["), "]")
+
+	var items []string
+
+	for rest != "" {
+		var item string
+
+		if quoted, err := strconv.QuotedPrefix(rest); err == nil {
+			item, _ = strconv.Unquote(quoted) // Can't fail, QuotedPrefix found a valid one
+			rest = strings.TrimPrefix(rest[len(quoted):], ", ")
+		} else {
+			item, rest, _ = strings.Cut(rest, ", ")
+		}
+
+		items = append(items, item)
+	}
+
+	return parse.Join(items, ",")
+}
+
+// describeRequested reports whether the [DescribeEnv] environment variable asks for the
+// spec of the command tree, removing it from the environment.
+func describeRequested() (bool, error) {
+	version, ok := os.LookupEnv(describeEnvVar)
+	if !ok {
+		return false, nil
+	}
+
+	if err := os.Unsetenv(describeEnvVar); err != nil {
+		return false, fmt.Errorf("could not unset %s: %w", describeEnvVar, err)
+	}
+
+	if version != describeVersion {
+		return false, fmt.Errorf("unsupported %s version %q, expected %q", describeEnvVar, version, describeVersion)
+	}
+
+	return true, nil
+}
+
+// writeDescription writes the spec of the command tree rooted at cmd to its stdout as JSON.
+func writeDescription(cmd *Command) error {
+	description, err := cmd.Describe()
+	if err != nil {
+		return err
+	}
+
+	out, err := json.MarshalIndent(description, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not encode command spec: %w", err)
+	}
+
+	fmt.Fprintln(cmd.Stdout(), string(out))
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"go.followtheprocess.codes/cli/internal/flag"
+	"go.followtheprocess.codes/cli/internal/parse"
+)
+
+// ErrParse is matched (with [errors.Is]) by every error caused by a flag or
+// argument being given a value that cannot be parsed into its type.
+var ErrParse = parse.Err
+
+// InvalidValueError is the error returned when a flag or argument is given a value
+// that cannot be parsed into its type, e.g. "--count notanumber".
+//
+// It may be extracted from an error returned by [Command.Execute] with [errors.As],
+// and matches [ErrParse] in a call to [errors.Is]. The underlying cause (e.g. [strconv.ErrSyntax])
+// is available to [errors.Is] and [errors.As] too.
+//
+//	var invalid *cli.InvalidValueError
+//	if errors.As(err, &invalid) {
+//		fmt.Printf("bad value %q for %s %s\n", invalid.Value, invalid.Kind, invalid.Name)
+//	}
+type InvalidValueError = parse.InvalidValueError
+
+// UnknownFlagError is the error returned when a flag is passed on the command
+// line that the command does not define.
+//
+// It may be extracted from an error returned by [Command.Execute] with [errors.As].
+type UnknownFlagError = flag.UnknownFlagError
+
+// MissingArgumentError is the error returned when a required positional argument
+// (one declared with [Arg] without an [ArgDefault]) is not provided.
+//
+// It may be extracted from an error returned by [Command.Execute] with [errors.As].
+type MissingArgumentError struct {
+	// Name is the name of the missing argument.
+	Name string
+}
+
+// Error implements the error interface for [MissingArgumentError].
+func (e *MissingArgumentError) Error() string {
+	return fmt.Sprintf("argument %q is required and no value was provided", e.Name)
+}
+
+// MissingFlagError is the error returned when a flag declared with [Required]
+// is not given a value.
+//
+// It may be extracted from an error returned by [Command.Execute] with [errors.As].
+type MissingFlagError struct {
+	// Name is the name of the missing flag.
+	Name string
+}
+
+// Error implements the error interface for [MissingFlagError].
+func (e *MissingFlagError) Error() string {
+	return fmt.Sprintf("flag --%s is required and no value was provided", e.Name)
+}
+
+// UnknownCommandError is the error returned when a subcommand is requested that
+// does not exist, e.g. a typo like "mytool sevre" for "mytool serve".
+//
+// It may be extracted from an error returned by [Command.Execute] with [errors.As].
+type UnknownCommandError struct {
+	// Name is the unknown command as it was typed.
+	Name string
+
+	// Parent is the full path to the command the unknown command was looked
+	// for under e.g. "mytool" or "mytool serve".
+	Parent string
+
+	// Suggestions are the names of any similarly named subcommands (or help topics)
+	// that the user may have meant.
+	Suggestions []string
+
+	// topics is whether help topics were searched too, it only changes the message.
+	topics bool
+}
+
+// Error implements the error interface for [UnknownCommandError].
+func (e *UnknownCommandError) Error() string {
+	s := &strings.Builder{}
+
+	if e.topics {
+		fmt.Fprintf(s, "unknown command or help topic %q for %q", e.Name, e.Parent)
+	} else {
+		fmt.Fprintf(s, "unknown command %q for %q", e.Name, e.Parent)
+	}
+
+	if len(e.Suggestions) != 0 {
+		s.WriteString("\n\nDid you mean this?\n\t")
+		s.WriteString(strings.Join(e.Suggestions, "\n\t"))
+	}
+
+	fmt.Fprintf(s, "\n\nRun '%s --help' for usage.", e.Parent)
+
+	return s.String()
+}
@@ -3,7 +3,11 @@ package flag
 
 import (
 	"net"
+	"net/netip"
 	"net/url"
+	"os"
+	"regexp"
+	"strconv"
 	"time"
 )
 
@@ -18,13 +22,63 @@ const NoShortHand = rune(-1)
 //   - -vvv
 //   - --verbose --verbose --verbose (although not sure why you'd do this)
 //   - --verbose=3
+//   - --verbose 3 (or -v 3)
 //
 // All have the same effect of increasing the verbosity level to 3.
 //
-// --verbose 3 however is not supported, this is due to an internal parsing
-// implementation detail.
+// The argument after a count flag is only taken as its value if it's a valid count and
+// not the name of a subcommand, so "--verbose file.txt" is a single increment followed
+// by the positional argument "file.txt".
 type Count uint
 
+// ByteSize is a type used for a flag holding a number of bytes, e.g. a "max-size" flag
+// given as "--max-size 10MiB".
+//
+// Sizes may be given in decimal (KB, MB, GB, TB, PB) or binary (KiB, MiB, GiB, TiB, PiB)
+// units, case insensitively, or as a plain number of bytes. Fractions are allowed as long as
+// they come to a whole number of bytes e.g. "1.5GB".
+type ByteSize uint64
+
+// Byte sizes in decimal and binary units, for defaults e.g. 10 * flag.MiB.
+const (
+	Byte ByteSize = 1
+
+	KB ByteSize = 1000 * Byte
+	MB ByteSize = 1000 * KB
+	GB ByteSize = 1000 * MB
+	TB ByteSize = 1000 * GB
+	PB ByteSize = 1000 * TB
+
+	KiB ByteSize = 1024 * Byte
+	MiB ByteSize = 1024 * KiB
+	GiB ByteSize = 1024 * MiB
+	TiB ByteSize = 1024 * GiB
+	PiB ByteSize = 1024 * TiB
+)
+
+// String implements [fmt.Stringer] for ByteSize, using the largest unit (binary or decimal)
+// that represents it exactly e.g. "10MiB" or "1500MB".
+func (b ByteSize) String() string {
+	units := [...]struct {
+		name string
+		size ByteSize
+	}{
+		{"PiB", PiB}, {"PB", PB},
+		{"TiB", TiB}, {"TB", TB},
+		{"GiB", GiB}, {"GB", GB},
+		{"MiB", MiB}, {"MB", MB},
+		{"KiB", KiB}, {"KB", KB},
+	}
+
+	for _, unit := range units {
+		if b >= unit.size && b%unit.size == 0 {
+			return strconv.FormatUint(uint64(b/unit.size), 10) + unit.name
+		}
+	}
+
+	return strconv.FormatUint(uint64(b), 10) + "B"
+}
+
 // Flaggable is a type constraint that defines any type capable of being parsed as a command line flag.
 type Flaggable interface {
 	int |
@@ -48,6 +102,12 @@ type Flaggable interface {
 		time.Duration |
 		net.IP |
 		*url.URL |
+		netip.Addr |
+		netip.Prefix |
+		netip.AddrPort |
+		*regexp.Regexp |
+		os.FileMode |
+		ByteSize |
 		[]int |
 		[]int8 |
 		[]int16 |
@@ -59,5 +119,9 @@ type Flaggable interface {
 		[]uint64 |
 		[]float32 |
 		[]float64 |
-		[]string
+		[]string |
+		[]bool |
+		[]time.Duration |
+		[]net.IP |
+		[]*url.URL
 }
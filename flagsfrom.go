@@ -0,0 +1,510 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"os"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"go.followtheprocess.codes/cli/arg"
+	"go.followtheprocess.codes/cli/flag"
+	internalarg "go.followtheprocess.codes/cli/internal/arg"
+	internalflag "go.followtheprocess.codes/cli/internal/flag"
+)
+
+const (
+	// flagTagKey is the struct tag declaring a flag (or a nested struct of flags) in [FlagsFrom].
+	flagTagKey = "cli"
+
+	// argTagKey is the struct tag declaring a positional argument in [FlagsFrom].
+	argTagKey = "arg"
+)
+
+// valueStructs are the struct types that are flag or argument values themselves,
+// rather than nested structs of flags.
+//
+//nolint:gochecknoglobals // Effectively a constant
+var valueStructs = []reflect.Type{
+	reflect.TypeFor[time.Time](),
+	reflect.TypeFor[netip.Addr](),
+	reflect.TypeFor[netip.Prefix](),
+	reflect.TypeFor[netip.AddrPort](),
+}
+
+type flagsFromOpt struct{ opts any }
+
+func (o flagsFromOpt) apply(cmd *Command) error {
+	value := reflect.ValueOf(o.opts)
+	if value.Kind() != reflect.Pointer || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("FlagsFrom requires a non-nil pointer to a struct, got %T", o.opts)
+	}
+
+	options, err := structOptions(value.Elem(), "", "")
+	if err != nil {
+		return fmt.Errorf("FlagsFrom(%T): %w", o.opts, err)
+	}
+
+	var errs error
+	for _, option := range options {
+		errs = errors.Join(errs, option.apply(cmd))
+	}
+
+	return errs
+}
+
+// FlagsFrom is an [Option] that adds a flag to a [Command] for every field of the struct
+// pointed to by opts that has a "cli" struct tag, and a positional argument for every
+// field with an "arg" tag. It's an alternative to a long list of [Flag] and [Arg] options
+// for commands with lots of them, and the parsed values are stored in the struct's fields.
+//
+//	type options struct {
+//		Port    int           `cli:"short=p,env=MYTOOL_PORT,usage=Port to bind,default=8080"`
+//		Token   string        `cli:"usage=API token,required"`
+//		Timeout time.Duration `cli:"name=wait,usage=How long to wait"`
+//		DB      struct {
+//			Host string `cli:"usage=Database host,default=localhost"`
+//		} `cli:"prefix=db,group=Database Options"`
+//		File string `arg:"usage=File to serve"`
+//	}
+//
+//	var opts options
+//	cli.New("serve", cli.FlagsFrom(&opts))
+//
+// A flag's tag is a comma separated list of the following keys, all of which are optional:
+//
+//   - name: the name of the flag, defaults to the field name in kebab-case e.g. "max-retries" for MaxRetries.
+//   - short: the single character shorthand, by default the flag has none.
+//   - env: see [Env].
+//   - usage: the flag's usage line.
+//   - default: the default value, parsed like a value given on the command line. Slice defaults
+//     are comma separated. If there's no default, the field's current value is used.
+//   - required: see [Required].
+//   - group: see [FlagGroup].
+//
+// A literal comma in a value must be escaped with a backslash e.g. `usage=One\, two`.
+//
+// A field holding a struct (other than a value type such as [time.Time] or [netip.Addr]) with a "cli" tag has its own tagged fields
+// added as flags, prefixed with the nested struct's "prefix" (the field name in kebab-case by
+// default) and listed under its "group" in the help text. An "arg" tag accepts the name, usage
+// and default keys, arguments are added in the order of the struct's fields.
+//
+// Fields without either tag, or with a tag of "-", are ignored. Fields of types that can't be
+// a flag or argument are an error.
+func FlagsFrom(opts any) Option {
+	return flagsFromOpt{opts: opts}
+}
+
+// fieldTag is a parsed "cli" or "arg" struct tag.
+type fieldTag struct {
+	values map[string]string // Key value pairs e.g. name=port
+	flags  map[string]bool   // Keys present without a value e.g. required
+}
+
+// structOptions returns the options for the flags and arguments declared by the
+// tagged fields of the struct v, with any flag names prefixed by prefix and placed
+// under group unless the field says otherwise.
+func structOptions(v reflect.Value, prefix, group string) ([]Option, error) {
+	var options []Option
+
+	for i := range v.NumField() {
+		field := v.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		cliTag, isFlag := field.Tag.Lookup(flagTagKey)
+		argTag, isArg := field.Tag.Lookup(argTagKey)
+
+		if isFlag && isArg {
+			return nil, fmt.Errorf("field %s: cannot have both %q and %q tags", field.Name, flagTagKey, argTagKey)
+		}
+
+		if (!isFlag && !isArg) || cliTag == "-" || argTag == "-" {
+			continue
+		}
+
+		target := v.Field(i).Addr()
+
+		switch {
+		case isArg:
+			tag, err := parseFieldTag(argTag, "name", "usage", "default")
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %w", field.Name, err)
+			}
+
+			name := tag.value("name", kebabCase(field.Name))
+
+			option, err := argOption(target.Interface(), name, tag)
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %w", field.Name, err)
+			}
+
+			options = append(options, option)
+		case field.Type.Kind() == reflect.Struct && !slices.Contains(valueStructs, field.Type):
+			tag, err := parseFieldTag(cliTag, "prefix", "group")
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %w", field.Name, err)
+			}
+
+			nested, err := structOptions(
+				target.Elem(),
+				joinPrefix(prefix, tag.value("prefix", kebabCase(field.Name))),
+				tag.value("group", group),
+			)
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %w", field.Name, err)
+			}
+
+			options = append(options, nested...)
+		default:
+			tag, err := parseFieldTag(cliTag, "name", "short", "env", "usage", "default", "required", "group")
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %w", field.Name, err)
+			}
+
+			name := joinPrefix(prefix, tag.value("name", kebabCase(field.Name)))
+
+			option, err := flagOption(target.Interface(), name, tag, group)
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %w", field.Name, err)
+			}
+
+			options = append(options, option)
+		}
+	}
+
+	return options, nil
+}
+
+// flagOption returns the [Flag] option for the struct field pointed to by target.
+func flagOption(target any, name string, tag fieldTag, group string) (Option, error) {
+	switch p := target.(type) {
+	case *int:
+		return typedFlag(p, name, tag, group)
+	case *int8:
+		return typedFlag(p, name, tag, group)
+	case *int16:
+		return typedFlag(p, name, tag, group)
+	case *int32:
+		return typedFlag(p, name, tag, group)
+	case *int64:
+		return typedFlag(p, name, tag, group)
+	case *uint:
+		return typedFlag(p, name, tag, group)
+	case *uint8:
+		return typedFlag(p, name, tag, group)
+	case *uint16:
+		return typedFlag(p, name, tag, group)
+	case *uint32:
+		return typedFlag(p, name, tag, group)
+	case *uint64:
+		return typedFlag(p, name, tag, group)
+	case *uintptr:
+		return typedFlag(p, name, tag, group)
+	case *float32:
+		return typedFlag(p, name, tag, group)
+	case *float64:
+		return typedFlag(p, name, tag, group)
+	case *string:
+		return typedFlag(p, name, tag, group)
+	case *bool:
+		return typedFlag(p, name, tag, group)
+	case *[]byte:
+		return typedFlag(p, name, tag, group)
+	case *flag.Count:
+		return typedFlag(p, name, tag, group)
+	case *time.Time:
+		return typedFlag(p, name, tag, group)
+	case *time.Duration:
+		return typedFlag(p, name, tag, group)
+	case *net.IP:
+		return typedFlag(p, name, tag, group)
+	case **url.URL:
+		return typedFlag(p, name, tag, group)
+	case *netip.Addr:
+		return typedFlag(p, name, tag, group)
+	case *netip.Prefix:
+		return typedFlag(p, name, tag, group)
+	case *netip.AddrPort:
+		return typedFlag(p, name, tag, group)
+	case **regexp.Regexp:
+		return typedFlag(p, name, tag, group)
+	case *os.FileMode:
+		return typedFlag(p, name, tag, group)
+	case *flag.ByteSize:
+		return typedFlag(p, name, tag, group)
+	case *[]int:
+		return typedFlag(p, name, tag, group)
+	case *[]int8:
+		return typedFlag(p, name, tag, group)
+	case *[]int16:
+		return typedFlag(p, name, tag, group)
+	case *[]int32:
+		return typedFlag(p, name, tag, group)
+	case *[]int64:
+		return typedFlag(p, name, tag, group)
+	case *[]uint:
+		return typedFlag(p, name, tag, group)
+	case *[]uint16:
+		return typedFlag(p, name, tag, group)
+	case *[]uint32:
+		return typedFlag(p, name, tag, group)
+	case *[]uint64:
+		return typedFlag(p, name, tag, group)
+	case *[]float32:
+		return typedFlag(p, name, tag, group)
+	case *[]float64:
+		return typedFlag(p, name, tag, group)
+	case *[]string:
+		return typedFlag(p, name, tag, group)
+	case *[]bool:
+		return typedFlag(p, name, tag, group)
+	case *[]time.Duration:
+		return typedFlag(p, name, tag, group)
+	case *[]net.IP:
+		return typedFlag(p, name, tag, group)
+	case *[]*url.URL:
+		return typedFlag(p, name, tag, group)
+	default:
+		return nil, fmt.Errorf("type %s cannot be a flag", reflect.TypeOf(target).Elem())
+	}
+}
+
+// typedFlag builds the [Flag] option for a field of type T from its tag.
+func typedFlag[T flag.Flaggable](target *T, name string, tag fieldTag, group string) (Option, error) {
+	short := flag.NoShortHand
+
+	if str, ok := tag.values["short"]; ok {
+		r, size := utf8.DecodeRuneInString(str)
+		if size == 0 || size != len(str) {
+			return nil, fmt.Errorf("short must be a single character, got %q", str)
+		}
+
+		short = r
+	}
+
+	var options []FlagOption[T]
+
+	if str, ok := tag.values["default"]; ok {
+		value, err := parseFlagDefault[T](name, str)
+		if err != nil {
+			return nil, err
+		}
+
+		options = append(options, FlagDefault(value))
+	} else if !reflect.ValueOf(target).Elem().IsZero() {
+		// The struct was given a value before being passed in, keep it as the default
+		options = append(options, FlagDefault(*target))
+	}
+
+	if env, ok := tag.values["env"]; ok {
+		options = append(options, Env[T](env))
+	}
+
+	if tag.flags["required"] {
+		options = append(options, Required[T]())
+	}
+
+	if group := tag.value("group", group); group != "" {
+		options = append(options, FlagGroup[T](group))
+	}
+
+	return Flag(target, name, short, tag.values["usage"], options...), nil
+}
+
+// parseFlagDefault parses a default value from a struct tag exactly as the flag
+// would parse it from the command line.
+func parseFlagDefault[T flag.Flaggable](name, str string) (T, error) {
+	var value T
+
+	f, err := internalflag.New(&value, name, flag.NoShortHand, "", internalflag.Config[T]{})
+	if err != nil {
+		return value, err
+	}
+
+	items := []string{str}
+	if f.IsSlice() {
+		items = strings.Split(str, ",")
+	}
+
+	for _, item := range items {
+		if err := f.Set(item); err != nil {
+			return value, fmt.Errorf("bad default: %w", err)
+		}
+	}
+
+	return value, nil
+}
+
+// argOption returns the [Arg] option for the struct field pointed to by target.
+func argOption(target any, name string, tag fieldTag) (Option, error) {
+	switch p := target.(type) {
+	case *int:
+		return typedArg(p, name, tag)
+	case *int8:
+		return typedArg(p, name, tag)
+	case *int16:
+		return typedArg(p, name, tag)
+	case *int32:
+		return typedArg(p, name, tag)
+	case *int64:
+		return typedArg(p, name, tag)
+	case *uint:
+		return typedArg(p, name, tag)
+	case *uint8:
+		return typedArg(p, name, tag)
+	case *uint16:
+		return typedArg(p, name, tag)
+	case *uint32:
+		return typedArg(p, name, tag)
+	case *uint64:
+		return typedArg(p, name, tag)
+	case *uintptr:
+		return typedArg(p, name, tag)
+	case *float32:
+		return typedArg(p, name, tag)
+	case *float64:
+		return typedArg(p, name, tag)
+	case *string:
+		return typedArg(p, name, tag)
+	case **url.URL:
+		return typedArg(p, name, tag)
+	case *bool:
+		return typedArg(p, name, tag)
+	case *[]byte:
+		return typedArg(p, name, tag)
+	case *time.Time:
+		return typedArg(p, name, tag)
+	case *time.Duration:
+		return typedArg(p, name, tag)
+	case *net.IP:
+		return typedArg(p, name, tag)
+	default:
+		return nil, fmt.Errorf("type %s cannot be an argument", reflect.TypeOf(target).Elem())
+	}
+}
+
+// typedArg builds the [Arg] option for a field of type T from its tag.
+func typedArg[T arg.Argable](target *T, name string, tag fieldTag) (Option, error) {
+	var options []ArgOption[T]
+
+	if str, ok := tag.values["default"]; ok {
+		var value T
+
+		parser, err := internalarg.New(&value, name, "", internalarg.Config[T]{})
+		if err != nil {
+			return nil, err
+		}
+
+		if err := parser.Set(str); err != nil {
+			return nil, fmt.Errorf("bad default: %w", err)
+		}
+
+		options = append(options, ArgDefault(value))
+	}
+
+	return Arg(target, name, tag.values["usage"], options...), nil
+}
+
+// parseFieldTag parses a struct tag of comma separated key=value pairs (or bare keys),
+// allowing only the keys given. A comma in a value may be escaped with a backslash.
+func parseFieldTag(tag string, allowed ...string) (fieldTag, error) {
+	parsed := fieldTag{values: make(map[string]string), flags: make(map[string]bool)}
+
+	for _, part := range splitEscaped(tag) {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+
+		key, value, hasValue := strings.Cut(part, "=")
+		key = strings.TrimSpace(key)
+
+		if !slices.Contains(allowed, key) {
+			return fieldTag{}, fmt.Errorf("unknown tag key %q, expected one of %s", key, strings.Join(allowed, ", "))
+		}
+
+		if _, seen := parsed.values[key]; seen || parsed.flags[key] {
+			return fieldTag{}, fmt.Errorf("duplicate tag key %q", key)
+		}
+
+		if hasValue {
+			parsed.values[key] = value
+		} else {
+			parsed.flags[key] = true
+		}
+	}
+
+	return parsed, nil
+}
+
+// value returns the value of key in the tag, or def if it isn't set.
+func (t fieldTag) value(key, def string) string {
+	if value, ok := t.values[key]; ok {
+		return value
+	}
+
+	return def
+}
+
+// splitEscaped splits s on commas, except those escaped with a backslash.
+func splitEscaped(s string) []string {
+	var (
+		parts   []string
+		current strings.Builder
+	)
+
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && i+1 < len(s) && s[i+1] == ',':
+			current.WriteByte(',')
+			i++
+		case s[i] == ',':
+			parts = append(parts, current.String())
+			current.Reset()
+		default:
+			current.WriteByte(s[i])
+		}
+	}
+
+	return append(parts, current.String())
+}
+
+// joinPrefix joins a nested struct's prefix onto name with a hyphen.
+func joinPrefix(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+
+	return prefix + "-" + name
+}
+
+// kebabCase converts a Go field name to kebab-case e.g. "MaxRetries" to "max-retries"
+// and "HTTPPort" to "http-port".
+func kebabCase(name string) string {
+	runes := []rune(name)
+	s := &strings.Builder{}
+
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				s.WriteByte('-')
+			}
+		}
+
+		s.WriteRune(unicode.ToLower(r))
+	}
+
+	return s.String()
+}
@@ -11,11 +11,11 @@ require (
 	go.followtheprocess.codes/hue v1.2.0
 	go.followtheprocess.codes/snapshot v0.11.0
 	go.followtheprocess.codes/test v1.4.0
+	go.yaml.in/yaml/v4 v4.0.0-rc.6
+	golang.org/x/term v0.44.0
 )
 
 require (
 	go.followtheprocess.codes/diff v0.2.0 // indirect
-	go.yaml.in/yaml/v4 v4.0.0-rc.6 // indirect
 	golang.org/x/sys v0.46.0 // indirect
-	golang.org/x/term v0.44.0 // indirect
 )
@@ -0,0 +1,155 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+const (
+	// helpCommandName is the name of the built-in help subcommand.
+	helpCommandName = "help"
+
+	// maxSuggestionDistance is the maximum edit distance between an unknown command
+	// and a real one for the real one to be offered as a suggestion.
+	maxSuggestionDistance = 2
+)
+
+// newHelpCommand builds the built-in help subcommand, which shows the help text
+// for any command in the tree beneath its parent, or one of the help topics defined on
+// it e.g. 'mytool help', 'mytool help serve start' or 'mytool help environment'.
+func newHelpCommand() (*Command, error) {
+	return New(
+		helpCommandName,
+		Short("Show help for a command or topic"),
+		Run(runHelp),
+	)
+}
+
+// runHelp is the run function for the built-in help subcommand.
+//
+// The words passed to help are resolved against the command tree in exactly the same
+// way as [Command.Execute] resolves its arguments, so 'mytool help serve start' shows
+// the same thing as 'mytool serve start --help'.
+func runHelp(_ context.Context, cmd *Command) error {
+	parent := cmd.parent
+	if parent == nil {
+		// Should be impossible, help is only ever added as a subcommand
+		return fmt.Errorf("%s command has no parent", helpCommandName)
+	}
+
+	target, rest, err := findRequestedCommand(parent, cmd.Args())
+	if err != nil {
+		return err
+	}
+
+	switch len(rest) {
+	case 0:
+		// Just 'mytool help', equivalent to 'mytool --help', or a full
+		// path to a command e.g. 'mytool help serve start'
+		return showHelp(target)
+	case 1:
+		if topic, ok := findTopic(target, rest[0]); ok {
+			showTopic(target, topic)
+
+			return nil
+		}
+	}
+
+	return unknownCommand(target, rest[0], true)
+}
+
+// findTopic searches the help topics defined on cmd for one called name.
+func findTopic(cmd *Command, name string) (helpTopic, bool) {
+	for _, topic := range cmd.topics {
+		if topic.name == name {
+			return topic, true
+		}
+	}
+
+	return helpTopic{}, false
+}
+
+// unknownCommand builds the error returned when name is not one of cmd's
+// subcommands (or help topics, if topics is true), suggesting any similarly named ones.
+func unknownCommand(cmd *Command, name string, topics bool) *UnknownCommandError {
+	candidates := make([]string, 0, len(cmd.subcommands)+len(cmd.topics))
+	for _, subcommand := range cmd.subcommands {
+		candidates = append(candidates, subcommand.name)
+	}
+
+	if topics {
+		for _, topic := range cmd.topics {
+			candidates = append(candidates, topic.name)
+		}
+	}
+
+	return &UnknownCommandError{
+		Name:        name,
+		Parent:      commandPath(cmd),
+		Suggestions: suggestions(name, candidates),
+		topics:      topics,
+	}
+}
+
+// suggestions returns the candidates that look like plausible typos of name, either
+// because they're within a small edit distance of it or because name is a prefix.
+func suggestions(name string, candidates []string) []string {
+	var similar []string
+
+	for _, candidate := range candidates {
+		if candidate == helpCommandName {
+			continue
+		}
+
+		if strings.HasPrefix(candidate, name) || levenshtein(name, candidate) <= maxSuggestionDistance {
+			similar = append(similar, candidate)
+		}
+	}
+
+	return similar
+}
+
+// commandPath returns the full, space separated path to cmd from the root
+// e.g. "mytool serve start".
+func commandPath(cmd *Command) string {
+	var path []string
+	for c := cmd; c != nil; c = c.parent {
+		path = append(path, c.name)
+	}
+
+	slices.Reverse(path)
+
+	return strings.Join(path, " ")
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b string) int {
+	s, t := []rune(a), []rune(b)
+
+	// Only ever need the previous row of the matrix
+	prev := make([]int, len(t)+1)
+	curr := make([]int, len(t)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(s); i++ {
+		curr[0] = i
+
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+
+		prev, curr = curr, prev
+	}
+
+	return prev[len(t)]
+}
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"net"
 	"net/url"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -77,6 +78,33 @@ func (a Arg[T]) Default() string {
 	return formatValue(a.kind, a.config.DefaultValue)
 }
 
+// FromFile reports whether the argument's value may be read from a file ("@path")
+// or stdin ("-") rather than given directly on the command line.
+func (a Arg[T]) FromFile() bool {
+	return a.config.FromFile
+}
+
+// Prompt reports whether the user should be prompted for the argument's value
+// if it was not given on the command line.
+func (a Arg[T]) Prompt() bool {
+	return a.config.Prompt
+}
+
+// Choices returns the values the argument may take, formatted as strings, or nil
+// if it may take any value.
+func (a Arg[T]) Choices() []string {
+	if len(a.config.Choices) == 0 {
+		return nil
+	}
+
+	choices := make([]string, 0, len(a.config.Choices))
+	for _, choice := range a.config.Choices {
+		choices = append(choices, formatValue(a.kind, &choice))
+	}
+
+	return choices
+}
+
 // String returns the string representation of the current value of the arg.
 func (a Arg[T]) String() string {
 	if a.value == nil {
@@ -97,12 +125,41 @@ func (a Arg[T]) Type() string {
 
 // Set sets an [Arg] value by parsing it's string value.
 //
-//nolint:gocognit,maintidx,cyclop // No other way of doing this realistically
+// If the argument has choices, a value that is not one of them is an error and
+// the argument keeps its previous value, as is a value rejected by any of the
+// argument's validators.
 func (a Arg[T]) Set(str string) error {
 	if a.value == nil {
 		return fmt.Errorf("cannot set value %s, arg.value was nil", str)
 	}
 
+	previous := *a.value
+
+	if err := a.set(str); err != nil {
+		return err
+	}
+
+	if choices := a.Choices(); len(choices) != 0 && !slices.Contains(choices, a.String()) {
+		*a.value = previous
+
+		return parse.Error(parse.KindArgument, a.name, str, previous, fmt.Errorf("must be one of %s", strings.Join(choices, ", ")))
+	}
+
+	for _, validator := range a.config.Validators {
+		if err := validator(*a.value); err != nil {
+			*a.value = previous
+
+			return parse.Error(parse.KindArgument, a.name, str, previous, err)
+		}
+	}
+
+	return nil
+}
+
+// set implements Set.
+//
+//nolint:gocognit,maintidx,cyclop // No other way of doing this realistically
+func (a Arg[T]) set(str string) error {
 	switch a.kind {
 	case kind.Int:
 		val, err := parse.Int(str)
@@ -245,7 +302,7 @@ func (a Arg[T]) Set(str string) error {
 
 		return nil
 	case kind.Time:
-		val, err := time.Parse(time.RFC3339, str)
+		val, err := parse.Time(str)
 		if err != nil {
 			return parse.Error(parse.KindArgument, a.name, str, *a.value, err)
 		}
@@ -11,4 +11,19 @@ type Config[T arg.Argable] struct {
 	// A non-nil value indicates the argument is not required and if not
 	// provided on the command line, will assume the value DefaultValue points to.
 	DefaultValue *T
+
+	// FromFile allows the argument's value to be read from a file ("@path") or
+	// stdin ("-") rather than given directly on the command line.
+	FromFile bool
+
+	// Prompt asks the user for the argument's value interactively if it was
+	// not given on the command line.
+	Prompt bool
+
+	// Choices is the set of values the argument may take, if empty any value is allowed.
+	Choices []T
+
+	// Validators are run, in order, on the argument's value after it's parsed. Any
+	// error rejects the value.
+	Validators []func(T) error
 }
@@ -20,4 +20,16 @@ type Value interface {
 	// Default returns the default value as a string, or "" if the argument
 	// is required.
 	Default() string
+
+	// FromFile reports whether the argument's value may be read from a file ("@path")
+	// or stdin ("-").
+	FromFile() bool
+
+	// Prompt reports whether the user should be prompted for the argument's value
+	// if it is not given on the command line.
+	Prompt() bool
+
+	// Choices returns the string representations of the values the argument may
+	// take, or nil if it may take any value.
+	Choices() []string
 }
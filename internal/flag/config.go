@@ -9,4 +9,43 @@ type Config[T flag.Flaggable] struct {
 	// EnvVar is the name of an environment variable that may set this flag's value
 	// if the flag is not explicitly provided on the command line.
 	EnvVar string
+	// Group is the title of the help section this flag is listed under, flags
+	// without a group are listed under the default "Options" section.
+	Group string
+	// FromFile allows the flag's value to be read from a file ("@path") or
+	// stdin ("-") rather than given directly on the command line.
+	FromFile bool
+	// Sensitive masks the flag's value everywhere it would otherwise be shown,
+	// e.g. in help text and error messages.
+	Sensitive bool
+	// Prompt asks the user for the flag's value interactively if it was not
+	// given on the command line or by an environment variable.
+	Prompt bool
+	// Choices is the set of values the flag may take, if empty any value is allowed.
+	Choices []T
+	// Required means the flag must be given a value, on the command line, by its
+	// environment variable or in answer to a prompt.
+	Required bool
+	// Validators are run, in order, on every value the flag is set to after it's
+	// parsed. Any error rejects the value.
+	Validators []func(T) error
+	// Deprecated, if not empty, marks the flag as deprecated, it is the message shown
+	// (e.g. "use --listen instead") whenever the flag is used.
+	Deprecated string
+	// ReplacedBy is the name of another flag that any values given to this one are
+	// forwarded to, for flags being renamed.
+	ReplacedBy string
+	// Hidden leaves the flag out of help text and completion, it may still be used.
+	Hidden bool
+	// Aliases are other long names the flag may be given by e.g. "color" for "colour".
+	Aliases []string
+	// NoArgDefault, if not nil, is the value the flag takes when given on its own
+	// e.g. --colour rather than --colour=never. Explicit values must then use "=".
+	NoArgDefault *T
+	// Delimiter, if not empty, splits each value given to a slice flag into items
+	// e.g. "," for --items one,two,three.
+	Delimiter string
+	// ReplaceDefault makes the values given to a slice flag replace its default,
+	// rather than being added to it.
+	ReplaceDefault bool
 }
@@ -7,8 +7,12 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"io/fs"
 	"net"
+	"net/netip"
 	"net/url"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -25,15 +29,30 @@ var _ Value = &Flag[string]{} // This will fail if we violate our Value interfac
 
 // Flag represents a single command line flag.
 type Flag[T flag.Flaggable] struct {
-	value      *T        // The actual stored value
-	name       string    // The name of the flag as appears on the command line, e.g. "force" for a --force flag
-	usage      string    // one line description of the flag, e.g. "Force deletion without confirmation"
-	envVar     string    // Name of an environment variable that may set this flag's value if the flag is not explicitly provided on the command line
-	typeStr    string    // Cached result of Type()
-	noArgValue string    // Cached result of NoArgValue()
-	short      rune      // Optional shorthand version of the flag, e.g. "f" for a -f flag
-	kind       kind.Kind // Cached concrete kind of T
-	isSlice    bool      // Cached result of IsSlice()
+	value      *T              // The actual stored value
+	defaultVal T               // The default value, restored by Reset
+	name       string          // The name of the flag as appears on the command line, e.g. "force" for a --force flag
+	usage      string          // one line description of the flag, e.g. "Force deletion without confirmation"
+	envVar     string          // Name of an environment variable that may set this flag's value if the flag is not explicitly provided on the command line
+	group      string          // Title of the help section the flag is listed under, "" for the default section
+	typeStr    string          // Cached result of Type()
+	noArgValue string          // Cached result of NoArgValue()
+	noArgDflt  string          // The value set by the NoArgDefault option, formatted, "" if none
+	short      rune            // Optional shorthand version of the flag, e.g. "f" for a -f flag
+	kind       kind.Kind       // Cached concrete kind of T
+	isSlice    bool            // Cached result of IsSlice()
+	fromFile   bool            // Whether the value may be read from a file or stdin
+	choices    []string        // Allowed values (formatted as strings), nil if any value is allowed
+	validators []func(T) error // Checks run on every value after it's parsed
+	deprecated string          // Deprecation message, "" if the flag is not deprecated
+	replacedBy string          // Name of the flag values are forwarded to, "" for none
+	hidden     bool            // Whether the flag is left out of help and completion
+	aliases    []string        // Other long names for the flag e.g. "color" for "colour"
+	delimiter  string          // Splits each value of a slice flag into items, "" for none
+	replace    bool            // Whether values replace the default of a slice flag rather than add to it
+	sensitive  bool            // Whether the value is masked in String and errors
+	prompt     bool            // Whether to prompt for the value if not provided
+	required   bool            // Whether the flag must be given a value
 }
 
 // New constructs and returns a new [Flag].
@@ -53,20 +72,90 @@ func New[T flag.Flaggable](p *T, name string, short rune, usage string, config C
 		return nil, fmt.Errorf("flag %q: target pointer must not be nil", name)
 	}
 
+	for i, alias := range config.Aliases {
+		if err := validateFlagName(alias); err != nil {
+			return nil, fmt.Errorf("invalid alias %q for flag %q: %w", alias, name, err)
+		}
+
+		if alias == name || slices.Contains(config.Aliases[:i], alias) {
+			return nil, fmt.Errorf("flag %q: duplicate alias %q", name, alias)
+		}
+	}
+
 	*p = config.DefaultValue
 
 	info := typeInfo[T]()
 
+	var choices []string
+
+	if len(config.Choices) != 0 {
+		if info.isSlice {
+			return nil, fmt.Errorf("flag %q: choices are not supported for slice flags", name)
+		}
+
+		choices = make([]string, 0, len(config.Choices))
+		for _, choice := range config.Choices {
+			formatter := Flag[T]{value: &choice, kind: info.kind}
+			choices = append(choices, formatter.format())
+		}
+	}
+
+	if config.Delimiter != "" && !info.isSlice {
+		return nil, fmt.Errorf("flag %q: delimiters are only supported for slice flags", name)
+	}
+
+	if config.ReplaceDefault && !info.isSlice {
+		return nil, fmt.Errorf("flag %q: replacing the default is only supported for slice flags", name)
+	}
+
+	noArgValue := info.noArgValue
+
+	var noArgDefault string
+
+	if config.NoArgDefault != nil {
+		if info.isSlice {
+			return nil, fmt.Errorf("flag %q: no-arg defaults are not supported for slice flags", name)
+		}
+
+		formatter := Flag[T]{value: config.NoArgDefault, kind: info.kind}
+		noArgDefault = formatter.format()
+
+		if noArgDefault == "" {
+			return nil, fmt.Errorf("flag %q: no-arg default must not be empty", name)
+		}
+
+		if len(choices) != 0 && !slices.Contains(choices, noArgDefault) {
+			return nil, fmt.Errorf("flag %q: no-arg default %q is not one of the choices %v", name, noArgDefault, choices)
+		}
+
+		noArgValue = noArgDefault
+	}
+
 	return &Flag[T]{
 		value:      p,
+		defaultVal: config.DefaultValue,
 		name:       name,
 		usage:      usage,
 		short:      short,
 		envVar:     config.EnvVar,
+		group:      config.Group,
 		typeStr:    info.typeStr,
-		noArgValue: info.noArgValue,
+		noArgValue: noArgValue,
+		noArgDflt:  noArgDefault,
 		kind:       info.kind,
 		isSlice:    info.isSlice,
+		fromFile:   config.FromFile,
+		sensitive:  config.Sensitive,
+		prompt:     config.Prompt,
+		required:   config.Required,
+		choices:    choices,
+		validators: config.Validators,
+		deprecated: config.Deprecated,
+		replacedBy: config.ReplacedBy,
+		hidden:     config.Hidden,
+		aliases:    config.Aliases,
+		delimiter:  config.Delimiter,
+		replace:    config.ReplaceDefault,
 	}, nil
 }
 
@@ -107,6 +196,75 @@ func (f *Flag[T]) EnvVar() string {
 	return f.envVar
 }
 
+// Group returns the title of the help section the flag is listed under, or
+// an empty string if it belongs to the default section.
+func (f *Flag[T]) Group() string {
+	return f.group
+}
+
+// FromFile reports whether the flag's value may be read from a file ("@path")
+// or stdin ("-") rather than given directly on the command line.
+func (f *Flag[T]) FromFile() bool {
+	return f.fromFile
+}
+
+// Sensitive reports whether the flag's value is masked in its String representation
+// (and therefore its Default) and in any errors from Set.
+func (f *Flag[T]) Sensitive() bool {
+	return f.sensitive
+}
+
+// Prompt reports whether the user should be prompted for the flag's value if it
+// was not given on the command line or by an environment variable.
+func (f *Flag[T]) Prompt() bool {
+	return f.prompt
+}
+
+// Required reports whether the flag must be given a value.
+func (f *Flag[T]) Required() bool {
+	return f.required
+}
+
+// Deprecated returns the deprecation message of the flag, or an empty string if
+// the flag is not deprecated.
+func (f *Flag[T]) Deprecated() string {
+	return f.deprecated
+}
+
+// ReplacedBy returns the name of the flag that values given to this one are
+// forwarded to, or an empty string if there isn't one.
+func (f *Flag[T]) ReplacedBy() string {
+	return f.replacedBy
+}
+
+// Hidden reports whether the flag is left out of help text and completion.
+func (f *Flag[T]) Hidden() bool {
+	return f.hidden
+}
+
+// Aliases returns the other long names the flag may be given by, if any.
+func (f *Flag[T]) Aliases() []string {
+	return f.aliases
+}
+
+// Delimiter returns the string that splits each value given to the flag into items,
+// or an empty string if values aren't split.
+func (f *Flag[T]) Delimiter() string {
+	return f.delimiter
+}
+
+// ReplaceDefault reports whether values given to the flag replace its default rather
+// than being added to it.
+func (f *Flag[T]) ReplaceDefault() bool {
+	return f.replace
+}
+
+// Choices returns the values the flag may take, formatted as strings, or nil
+// if it may take any value.
+func (f *Flag[T]) Choices() []string {
+	return f.choices
+}
+
 // IsSlice reports whether the flag holds a slice value that accumulates repeated
 // calls to Set. Returns false for []byte and net.IP, which are parsed atomically.
 func (f *Flag[T]) IsSlice() bool {
@@ -120,6 +278,50 @@ func (f *Flag[T]) NoArgValue() string {
 	return f.noArgValue
 }
 
+// NoArgDefault returns the value set for the flag to take when given on its own with
+// the NoArgDefault option, or an empty string if there isn't one. Unlike NoArgValue
+// it is empty for flags that imply a value from their type e.g. bool.
+func (f *Flag[T]) NoArgDefault() string {
+	return f.noArgDflt
+}
+
+// TakesNext reports whether the flag, when given on its own, takes arg (the next argument
+// on the command line) as its value rather than its NoArgValue.
+//
+// Only count flags do, and only if arg is a valid count, so "--verbose 3" works the same as
+// "--verbose=3" while "--verbose file.txt" leaves file.txt as a positional argument. Flags
+// given a NoArgDefault never do, their values must be given with an "=".
+func (f *Flag[T]) TakesNext(arg string) bool {
+	if f.kind != kind.Count || f.noArgDflt != "" {
+		return false
+	}
+
+	_, err := parse.Uint(arg)
+
+	return err == nil
+}
+
+// Reset restores the flag to its default value, as if it had never been set.
+func (f *Flag[T]) Reset() {
+	if f.value == nil {
+		return
+	}
+
+	*f.value = f.defaultVal
+}
+
+// Clear sets the flag to the zero value of its type, e.g. an empty slice, so that
+// subsequent calls to Set start from nothing rather than the default.
+func (f *Flag[T]) Clear() {
+	if f.value == nil {
+		return
+	}
+
+	var zero T
+
+	*f.value = zero
+}
+
 // Type returns a string representation of the type of the Flag.
 func (f *Flag[T]) Type() string {
 	if f.value == nil {
@@ -133,12 +335,24 @@ func (f *Flag[T]) Type() string {
 // String implements [fmt.Stringer] for a [Flag], and also implements the String
 // part of [Value], allowing a flag to print itself.
 //
-//nolint:cyclop // No other way of doing this realistically
+// Sensitive flags print a fixed mask rather than their value, unless they
+// hold the zero value.
 func (f *Flag[T]) String() string {
 	if f.value == nil {
 		return format.Nil
 	}
 
+	if f.sensitive && !f.isZeroIsh() {
+		return parse.Mask
+	}
+
+	return f.format()
+}
+
+// format returns the string representation of the stored value.
+//
+//nolint:cyclop // No other way of doing this realistically
+func (f *Flag[T]) format() string {
 	switch f.kind {
 	case kind.Int:
 		return format.Int(*parse.Cast[int](f.value))
@@ -187,6 +401,23 @@ func (f *Flag[T]) String() string {
 		}
 
 		return u.String()
+	case kind.Addr:
+		return formatValid(*parse.Cast[netip.Addr](f.value))
+	case kind.Prefix:
+		return formatValid(*parse.Cast[netip.Prefix](f.value))
+	case kind.AddrPort:
+		return formatValid(*parse.Cast[netip.AddrPort](f.value))
+	case kind.Regexp:
+		re := *parse.Cast[*regexp.Regexp](f.value)
+		if re == nil {
+			return format.Nil
+		}
+
+		return re.String()
+	case kind.FileMode:
+		return format.FileMode(*parse.Cast[fs.FileMode](f.value))
+	case kind.ByteSize:
+		return parse.Cast[flag.ByteSize](f.value).String()
 	case kind.IntSlice:
 		return format.Slice(*parse.Cast[[]int](f.value))
 	case kind.Int8Slice:
@@ -211,6 +442,14 @@ func (f *Flag[T]) String() string {
 		return format.Slice(*parse.Cast[[]float64](f.value))
 	case kind.StringSlice:
 		return format.Slice(*parse.Cast[[]string](f.value))
+	case kind.BoolSlice:
+		return format.Slice(*parse.Cast[[]bool](f.value))
+	case kind.DurationSlice:
+		return format.Slice(*parse.Cast[[]time.Duration](f.value))
+	case kind.IPSlice:
+		return format.Slice(*parse.Cast[[]net.IP](f.value))
+	case kind.URLSlice:
+		return format.Slice(*parse.Cast[[]*url.URL](f.value))
 	default:
 		return fmt.Sprintf("Flag.String: unsupported flag type: %T", *f.value)
 	}
@@ -274,6 +513,18 @@ func typeInfo[T flag.Flaggable]() info { //nolint:cyclop // No other way of doin
 		return info{kind: kind.IP, typeStr: format.TypeIP}
 	case *url.URL:
 		return info{kind: kind.URL, typeStr: format.TypeURL}
+	case netip.Addr:
+		return info{kind: kind.Addr, typeStr: format.TypeAddr}
+	case netip.Prefix:
+		return info{kind: kind.Prefix, typeStr: format.TypePrefix}
+	case netip.AddrPort:
+		return info{kind: kind.AddrPort, typeStr: format.TypeAddrPort}
+	case *regexp.Regexp:
+		return info{kind: kind.Regexp, typeStr: format.TypeRegexp}
+	case fs.FileMode:
+		return info{kind: kind.FileMode, typeStr: format.TypeFileMode}
+	case flag.ByteSize:
+		return info{kind: kind.ByteSize, typeStr: format.TypeByteSize}
 	case []int:
 		return info{kind: kind.IntSlice, typeStr: format.TypeIntSlice, isSlice: true}
 	case []int8:
@@ -298,6 +549,14 @@ func typeInfo[T flag.Flaggable]() info { //nolint:cyclop // No other way of doin
 		return info{kind: kind.Float64Slice, typeStr: format.TypeFloat64Slice, isSlice: true}
 	case []string:
 		return info{kind: kind.StringSlice, typeStr: format.TypeStringSlice, isSlice: true}
+	case []bool:
+		return info{kind: kind.BoolSlice, typeStr: format.TypeBoolSlice, isSlice: true}
+	case []time.Duration:
+		return info{kind: kind.DurationSlice, typeStr: format.TypeDurationSlice, isSlice: true}
+	case []net.IP:
+		return info{kind: kind.IPSlice, typeStr: format.TypeIPSlice, isSlice: true}
+	case []*url.URL:
+		return info{kind: kind.URLSlice, typeStr: format.TypeURLSlice, isSlice: true}
 	default:
 		return info{kind: kind.Invalid, typeStr: fmt.Sprintf("%T", typ)}
 	}
@@ -305,12 +564,44 @@ func typeInfo[T flag.Flaggable]() info { //nolint:cyclop // No other way of doin
 
 // Set sets a [Flag] value based on string input, i.e. parsing from the command line.
 //
-//nolint:gocognit,maintidx,cyclop // No other way of doing this realistically
+// If the flag has choices, a value that is not one of them is an error and the
+// flag keeps its previous value, as is a value rejected by any of the flag's
+// validators. If the flag is sensitive, the value is masked in any error returned.
 func (f *Flag[T]) Set(str string) error {
 	if f.value == nil {
 		return fmt.Errorf("cannot set value %s, flag.value was nil", str)
 	}
 
+	previous := *f.value
+
+	err := f.set(str)
+	if err == nil && len(f.choices) != 0 && !slices.Contains(f.choices, f.format()) {
+		*f.value = previous
+		err = parse.Error(parse.KindFlag, f.name, str, previous, fmt.Errorf("must be one of %s", strings.Join(f.choices, ", ")))
+	}
+
+	for _, validator := range f.validators {
+		if err != nil {
+			break
+		}
+
+		if invalid := validator(*f.value); invalid != nil {
+			*f.value = previous
+			err = parse.Error(parse.KindFlag, f.name, str, previous, invalid)
+		}
+	}
+
+	if f.sensitive {
+		return parse.Redact(err)
+	}
+
+	return err
+}
+
+// set implements Set.
+//
+//nolint:gocognit,maintidx,cyclop // No other way of doing this realistically
+func (f *Flag[T]) set(str string) error {
 	switch f.kind {
 	case kind.Int:
 		val, err := parse.Int(str)
@@ -457,7 +748,7 @@ func (f *Flag[T]) Set(str string) error {
 
 		return nil
 	case kind.Time:
-		val, err := time.Parse(time.RFC3339, str)
+		val, err := parse.Time(str)
 		if err != nil {
 			return parse.Error(parse.KindFlag, f.name, str, *f.value, err)
 		}
@@ -491,6 +782,60 @@ func (f *Flag[T]) Set(str string) error {
 
 		*f.value = *parse.Cast[T](&val)
 
+		return nil
+	case kind.Addr:
+		val, err := netip.ParseAddr(str)
+		if err != nil {
+			return parse.Error(parse.KindFlag, f.name, str, *f.value, err)
+		}
+
+		*f.value = *parse.Cast[T](&val)
+
+		return nil
+	case kind.Prefix:
+		val, err := netip.ParsePrefix(str)
+		if err != nil {
+			return parse.Error(parse.KindFlag, f.name, str, *f.value, err)
+		}
+
+		*f.value = *parse.Cast[T](&val)
+
+		return nil
+	case kind.AddrPort:
+		val, err := netip.ParseAddrPort(str)
+		if err != nil {
+			return parse.Error(parse.KindFlag, f.name, str, *f.value, err)
+		}
+
+		*f.value = *parse.Cast[T](&val)
+
+		return nil
+	case kind.Regexp:
+		val, err := regexp.Compile(str)
+		if err != nil {
+			return parse.Error(parse.KindFlag, f.name, str, *f.value, err)
+		}
+
+		*f.value = *parse.Cast[T](&val)
+
+		return nil
+	case kind.FileMode:
+		val, err := parse.FileMode(str)
+		if err != nil {
+			return parse.Error(parse.KindFlag, f.name, str, *f.value, err)
+		}
+
+		*f.value = *parse.Cast[T](&val)
+
+		return nil
+	case kind.ByteSize:
+		val, err := parse.ByteSize(str)
+		if err != nil {
+			return parse.Error(parse.KindFlag, f.name, str, *f.value, err)
+		}
+
+		*f.value = *parse.Cast[T](&val)
+
 		return nil
 	case kind.IntSlice:
 		// Like Count, a slice flag is a read/write op
@@ -608,12 +953,68 @@ func (f *Flag[T]) Set(str string) error {
 		typ = append(typ, str)
 		*f.value = *parse.Cast[T](&typ)
 
+		return nil
+	case kind.BoolSlice:
+		newValue, err := strconv.ParseBool(str)
+		if err != nil {
+			return parse.ErrorSlice(parse.KindFlag, f.name, str, *f.value, err)
+		}
+
+		typ := append(*parse.Cast[[]bool](f.value), newValue)
+		*f.value = *parse.Cast[T](&typ)
+
+		return nil
+	case kind.DurationSlice:
+		newValue, err := time.ParseDuration(str)
+		if err != nil {
+			return parse.ErrorSlice(parse.KindFlag, f.name, str, *f.value, err)
+		}
+
+		typ := append(*parse.Cast[[]time.Duration](f.value), newValue)
+		*f.value = *parse.Cast[T](&typ)
+
+		return nil
+	case kind.IPSlice:
+		newValue := net.ParseIP(str)
+		if newValue == nil {
+			return parse.ErrorSlice(parse.KindFlag, f.name, str, *f.value, errors.New("invalid IP address"))
+		}
+
+		typ := append(*parse.Cast[[]net.IP](f.value), newValue)
+		*f.value = *parse.Cast[T](&typ)
+
+		return nil
+	case kind.URLSlice:
+		newValue, err := url.ParseRequestURI(str)
+		if err != nil {
+			return parse.ErrorSlice(parse.KindFlag, f.name, str, *f.value, err)
+		}
+
+		typ := append(*parse.Cast[[]*url.URL](f.value), newValue)
+		*f.value = *parse.Cast[T](&typ)
+
 		return nil
 	default:
 		return fmt.Errorf("Flag.Set: unsupported flag type: %T", *f.value)
 	}
 }
 
+// validStringer is a value whose zero value is invalid, e.g. [netip.Addr].
+type validStringer interface {
+	IsValid() bool
+	String() string
+}
+
+// formatValid returns the string representation of value, or an empty string
+// for its zero value rather than e.g. "invalid IP".
+func formatValid[V validStringer](value V) string {
+	if !value.IsValid() {
+		return ""
+	}
+
+	return value.String()
+}
+
 // validateFlagName ensures a flag name is valid, returning an error if it's not.
 //
 // Flags names must be all lower case ASCII letters, a hyphen separator is allowed e.g. "set-default"
@@ -726,6 +1127,18 @@ func (f *Flag[T]) isZeroIsh() bool {
 		return len(*parse.Cast[net.IP](f.value)) == 0
 	case kind.URL:
 		return *parse.Cast[*url.URL](f.value) == nil
+	case kind.Addr:
+		return !parse.Cast[netip.Addr](f.value).IsValid()
+	case kind.Prefix:
+		return !parse.Cast[netip.Prefix](f.value).IsValid()
+	case kind.AddrPort:
+		return !parse.Cast[netip.AddrPort](f.value).IsValid()
+	case kind.Regexp:
+		return *parse.Cast[*regexp.Regexp](f.value) == nil
+	case kind.FileMode:
+		return *parse.Cast[fs.FileMode](f.value) == 0
+	case kind.ByteSize:
+		return *parse.Cast[flag.ByteSize](f.value) == 0
 	case kind.IntSlice:
 		return len(*parse.Cast[[]int](f.value)) == 0
 	case kind.Int8Slice:
@@ -750,6 +1163,14 @@ func (f *Flag[T]) isZeroIsh() bool {
 		return len(*parse.Cast[[]float64](f.value)) == 0
 	case kind.StringSlice:
 		return len(*parse.Cast[[]string](f.value)) == 0
+	case kind.BoolSlice:
+		return len(*parse.Cast[[]bool](f.value)) == 0
+	case kind.DurationSlice:
+		return len(*parse.Cast[[]time.Duration](f.value)) == 0
+	case kind.IPSlice:
+		return len(*parse.Cast[[]net.IP](f.value)) == 0
+	case kind.URLSlice:
+		return len(*parse.Cast[[]*url.URL](f.value)) == 0
 	case kind.Time:
 		var zero time.Time
 
@@ -4,7 +4,10 @@ import (
 	"bytes"
 	"errors"
 	"net"
+	"net/netip"
 	"net/url"
+	"os"
+	"regexp"
 	"slices"
 	"testing"
 	"time"
@@ -941,6 +944,271 @@ func TestFlaggableTypes(t *testing.T) {
 		test.Err(t, err)
 		test.True(t, errors.Is(err, parse.Err))
 	})
+
+	t.Run("addr valid", func(t *testing.T) {
+		var addr netip.Addr
+
+		addrFlag, err := flag.New(&addr, "addr", 'a', "Set an address", flag.Config[netip.Addr]{})
+		test.Ok(t, err)
+		test.Equal(t, addrFlag.String(), "")
+
+		err = addrFlag.Set("192.0.2.1")
+		test.Ok(t, err)
+		test.Equal(t, addr, netip.MustParseAddr("192.0.2.1"))
+		test.Equal(t, addrFlag.Type(), "addr")
+		test.Equal(t, addrFlag.String(), "192.0.2.1")
+	})
+
+	t.Run("addr invalid", func(t *testing.T) {
+		var addr netip.Addr
+
+		addrFlag, err := flag.New(&addr, "addr", 'a', "Set an address", flag.Config[netip.Addr]{})
+		test.Ok(t, err)
+
+		err = addrFlag.Set("not an addr")
+		test.Err(t, err)
+		test.True(t, errors.Is(err, parse.Err))
+	})
+
+	t.Run("prefix valid", func(t *testing.T) {
+		var prefix netip.Prefix
+
+		prefixFlag, err := flag.New(&prefix, "prefix", 'p', "Set a prefix", flag.Config[netip.Prefix]{})
+		test.Ok(t, err)
+
+		err = prefixFlag.Set("10.0.0.0/8")
+		test.Ok(t, err)
+		test.Equal(t, prefix, netip.MustParsePrefix("10.0.0.0/8"))
+		test.Equal(t, prefixFlag.Type(), "prefix")
+		test.Equal(t, prefixFlag.String(), "10.0.0.0/8")
+	})
+
+	t.Run("prefix invalid", func(t *testing.T) {
+		var prefix netip.Prefix
+
+		prefixFlag, err := flag.New(&prefix, "prefix", 'p', "Set a prefix", flag.Config[netip.Prefix]{})
+		test.Ok(t, err)
+
+		err = prefixFlag.Set("10.0.0.0/99")
+		test.Err(t, err)
+		test.True(t, errors.Is(err, parse.Err))
+	})
+
+	t.Run("addrPort valid", func(t *testing.T) {
+		var addrPort netip.AddrPort
+
+		addrPortFlag, err := flag.New(&addrPort, "listen", 'l', "Set an address and port", flag.Config[netip.AddrPort]{})
+		test.Ok(t, err)
+
+		err = addrPortFlag.Set("127.0.0.1:8080")
+		test.Ok(t, err)
+		test.Equal(t, addrPort, netip.MustParseAddrPort("127.0.0.1:8080"))
+		test.Equal(t, addrPortFlag.Type(), "addrPort")
+		test.Equal(t, addrPortFlag.String(), "127.0.0.1:8080")
+	})
+
+	t.Run("addrPort invalid", func(t *testing.T) {
+		var addrPort netip.AddrPort
+
+		addrPortFlag, err := flag.New(&addrPort, "listen", 'l', "Set an address and port", flag.Config[netip.AddrPort]{})
+		test.Ok(t, err)
+
+		err = addrPortFlag.Set("127.0.0.1")
+		test.Err(t, err)
+		test.True(t, errors.Is(err, parse.Err))
+	})
+
+	t.Run("regexp valid", func(t *testing.T) {
+		var re *regexp.Regexp
+
+		reFlag, err := flag.New(&re, "match", 'm', "Set a pattern", flag.Config[*regexp.Regexp]{})
+		test.Ok(t, err)
+
+		err = reFlag.Set("^v[0-9]+$")
+		test.Ok(t, err)
+		test.True(t, re.MatchString("v12"))
+		test.Equal(t, reFlag.Type(), "regexp")
+		test.Equal(t, reFlag.String(), "^v[0-9]+$")
+	})
+
+	t.Run("regexp invalid", func(t *testing.T) {
+		var re *regexp.Regexp
+
+		reFlag, err := flag.New(&re, "match", 'm', "Set a pattern", flag.Config[*regexp.Regexp]{})
+		test.Ok(t, err)
+
+		err = reFlag.Set("(unclosed")
+		test.Err(t, err)
+		test.True(t, errors.Is(err, parse.Err))
+	})
+
+	t.Run("fileMode valid", func(t *testing.T) {
+		var mode os.FileMode
+
+		modeFlag, err := flag.New(&mode, "mode", 'm', "Set file permissions", flag.Config[os.FileMode]{})
+		test.Ok(t, err)
+
+		err = modeFlag.Set("0755")
+		test.Ok(t, err)
+		test.Equal(t, mode, os.FileMode(0o755))
+		test.Equal(t, modeFlag.Type(), "fileMode")
+		test.Equal(t, modeFlag.String(), "0755")
+	})
+
+	t.Run("fileMode invalid", func(t *testing.T) {
+		var mode os.FileMode
+
+		modeFlag, err := flag.New(&mode, "mode", 'm', "Set file permissions", flag.Config[os.FileMode]{})
+		test.Ok(t, err)
+
+		err = modeFlag.Set("999")
+		test.Err(t, err)
+		test.True(t, errors.Is(err, parse.Err))
+	})
+
+	t.Run("byteSize valid", func(t *testing.T) {
+		var size publicflag.ByteSize
+
+		sizeFlag, err := flag.New(&size, "size", 's', "Set a size", flag.Config[publicflag.ByteSize]{})
+		test.Ok(t, err)
+
+		err = sizeFlag.Set("10MiB")
+		test.Ok(t, err)
+		test.Equal(t, size, 10*publicflag.MiB)
+		test.Equal(t, sizeFlag.Type(), "byteSize")
+		test.Equal(t, sizeFlag.String(), "10MiB")
+
+		err = sizeFlag.Set("1.5GB")
+		test.Ok(t, err)
+		test.Equal(t, size, 1500*publicflag.MB)
+		test.Equal(t, sizeFlag.String(), "1500MB")
+	})
+
+	t.Run("byteSize invalid", func(t *testing.T) {
+		var size publicflag.ByteSize
+
+		sizeFlag, err := flag.New(&size, "size", 's', "Set a size", flag.Config[publicflag.ByteSize]{})
+		test.Ok(t, err)
+
+		err = sizeFlag.Set("10 parsecs")
+		test.Err(t, err)
+		test.True(t, errors.Is(err, parse.Err))
+	})
+
+	t.Run("bool slice valid", func(t *testing.T) {
+		var slice []bool
+
+		sliceFlag, err := flag.New(&slice, "slice", 's', "Append to a slice of bools", flag.Config[[]bool]{})
+		test.Ok(t, err)
+
+		err = sliceFlag.Set("true")
+		test.Ok(t, err)
+
+		err = sliceFlag.Set("false")
+		test.Ok(t, err)
+
+		test.EqualFunc(t, slice, []bool{true, false}, slices.Equal)
+		test.Equal(t, sliceFlag.Type(), "[]bool")
+		test.Equal(t, sliceFlag.String(), "[true, false]")
+	})
+
+	t.Run("bool slice invalid", func(t *testing.T) {
+		var slice []bool
+
+		sliceFlag, err := flag.New(&slice, "slice", 's', "Append to a slice of bools", flag.Config[[]bool]{})
+		test.Ok(t, err)
+
+		err = sliceFlag.Set("maybe")
+		test.Err(t, err)
+		test.True(t, errors.Is(err, parse.Err))
+	})
+
+	t.Run("duration slice valid", func(t *testing.T) {
+		var slice []time.Duration
+
+		sliceFlag, err := flag.New(&slice, "backoff", 'b', "Append to a slice of durations", flag.Config[[]time.Duration]{})
+		test.Ok(t, err)
+
+		err = sliceFlag.Set("1s")
+		test.Ok(t, err)
+
+		err = sliceFlag.Set("1m30s")
+		test.Ok(t, err)
+
+		test.EqualFunc(t, slice, []time.Duration{time.Second, 90 * time.Second}, slices.Equal)
+		test.Equal(t, sliceFlag.Type(), "[]duration")
+		test.Equal(t, sliceFlag.String(), "[1s, 1m30s]")
+	})
+
+	t.Run("duration slice invalid", func(t *testing.T) {
+		var slice []time.Duration
+
+		sliceFlag, err := flag.New(&slice, "backoff", 'b', "Append to a slice of durations", flag.Config[[]time.Duration]{})
+		test.Ok(t, err)
+
+		err = sliceFlag.Set("soon")
+		test.Err(t, err)
+		test.True(t, errors.Is(err, parse.Err))
+	})
+
+	t.Run("ip slice valid", func(t *testing.T) {
+		var slice []net.IP
+
+		sliceFlag, err := flag.New(&slice, "ips", 'i', "Append to a slice of IPs", flag.Config[[]net.IP]{})
+		test.Ok(t, err)
+
+		err = sliceFlag.Set("192.0.2.1")
+		test.Ok(t, err)
+
+		err = sliceFlag.Set("::1")
+		test.Ok(t, err)
+
+		test.Equal(t, len(slice), 2)
+		test.True(t, slice[0].Equal(net.ParseIP("192.0.2.1")))
+		test.True(t, slice[1].Equal(net.IPv6loopback))
+		test.Equal(t, sliceFlag.Type(), "[]ip")
+		test.Equal(t, sliceFlag.String(), "[192.0.2.1, ::1]")
+	})
+
+	t.Run("ip slice invalid", func(t *testing.T) {
+		var slice []net.IP
+
+		sliceFlag, err := flag.New(&slice, "ips", 'i', "Append to a slice of IPs", flag.Config[[]net.IP]{})
+		test.Ok(t, err)
+
+		err = sliceFlag.Set("not an ip")
+		test.Err(t, err)
+		test.True(t, errors.Is(err, parse.Err))
+	})
+
+	t.Run("url slice valid", func(t *testing.T) {
+		var slice []*url.URL
+
+		sliceFlag, err := flag.New(&slice, "urls", 'u', "Append to a slice of URLs", flag.Config[[]*url.URL]{})
+		test.Ok(t, err)
+
+		err = sliceFlag.Set("https://example.com")
+		test.Ok(t, err)
+
+		err = sliceFlag.Set("https://example.org/path")
+		test.Ok(t, err)
+
+		test.Equal(t, len(slice), 2)
+		test.Equal(t, slice[1].Host, "example.org")
+		test.Equal(t, sliceFlag.Type(), "[]url")
+		test.Equal(t, sliceFlag.String(), "[https://example.com, https://example.org/path]")
+	})
+
+	t.Run("url slice invalid", func(t *testing.T) {
+		var slice []*url.URL
+
+		sliceFlag, err := flag.New(&slice, "urls", 'u', "Append to a slice of URLs", flag.Config[[]*url.URL]{})
+		test.Ok(t, err)
+
+		err = sliceFlag.Set("not a url")
+		test.Err(t, err)
+		test.True(t, errors.Is(err, parse.Err))
+	})
 }
 
 func TestFlagValidation(t *testing.T) {
@@ -1064,6 +1332,361 @@ func TestFlagValidation(t *testing.T) {
 	}
 }
 
+func TestSensitive(t *testing.T) {
+	var key string
+
+	f, err := flag.New(&key, "api-key", publicflag.NoShortHand, "API key", flag.Config[string]{Sensitive: true})
+	test.Ok(t, err)
+
+	test.True(t, f.Sensitive())
+
+	// Nothing to hide yet
+	test.Equal(t, f.String(), "")
+	test.Equal(t, f.Default(), "")
+
+	test.Ok(t, f.Set("hunter2"))
+	test.Equal(t, key, "hunter2") // The real value is still stored
+	test.Equal(t, f.String(), parse.Mask)
+	test.Equal(t, f.Default(), parse.Mask)
+
+	var port int
+
+	p, err := flag.New(&port, "port", 'p', "Secret port", flag.Config[int]{Sensitive: true})
+	test.Ok(t, err)
+
+	err = p.Set("hunter2")
+	test.Err(t, err)
+	test.Equal(t, err.Error(), `parse error: flag "port" received invalid value "******" (expected int)`)
+
+	var invalid *parse.InvalidValueError
+	test.True(t, errors.As(err, &invalid))
+	test.Equal(t, invalid.Value, parse.Mask)
+
+	t.Run("split", func(t *testing.T) {
+		tests := []struct {
+			name   string   // Name of the test case
+			env    string   // Value of the KEYS env var, unset if empty
+			errMsg string   // Expected error message
+			args   []string // Arguments to parse
+		}{
+			{
+				name:   "command line",
+				args:   []string{"--keys", `"hunter2,other`},
+				errMsg: `flag --keys: unterminated quote in "******"`,
+			},
+			{
+				name:   "env var",
+				env:    `"hunter2,other`,
+				errMsg: `could not set flag from env: env var KEYS: unterminated quote in "******"`,
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				if tt.env != "" {
+					t.Setenv("KEYS", tt.env)
+				}
+
+				var keys []string
+
+				f, err := flag.New(&keys, "keys", 'k', "API keys", flag.Config[[]string]{
+					Delimiter: ",",
+					EnvVar:    "KEYS",
+					Sensitive: true,
+				})
+				test.Ok(t, err)
+
+				set := flag.NewSet()
+				test.Ok(t, flag.AddToSet(set, f))
+
+				err = set.Parse(tt.args)
+				test.Err(t, err)
+				test.Equal(t, err.Error(), tt.errMsg)
+				test.True(t, errors.Is(err, parse.ErrUnterminatedQuote))
+			})
+		}
+	})
+}
+
+func TestNoArgDefault(t *testing.T) {
+	t.Run("parse", func(t *testing.T) {
+		tests := []struct {
+			name   string   // Name of the test case
+			want   string   // Expected value of the flag
+			args   []string // Arguments to parse
+			remain []string // Expected positional arguments left over
+		}{
+			{
+				name:   "not given",
+				args:   []string{"file.txt"},
+				want:   "auto",
+				remain: []string{"file.txt"},
+			},
+			{
+				name:   "bare",
+				args:   []string{"--colour", "file.txt"},
+				want:   "always",
+				remain: []string{"file.txt"},
+			},
+			{
+				name:   "explicit",
+				args:   []string{"--colour=never", "file.txt"},
+				want:   "never",
+				remain: []string{"file.txt"},
+			},
+			{
+				name:   "bare short",
+				args:   []string{"-c", "file.txt"},
+				want:   "always",
+				remain: []string{"file.txt"},
+			},
+			{
+				name:   "explicit short",
+				args:   []string{"-c=never"},
+				want:   "never",
+				remain: nil,
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				var colour string
+
+				always := "always"
+				f, err := flag.New(&colour, "colour", 'c', "Colour the output", flag.Config[string]{
+					DefaultValue: "auto",
+					NoArgDefault: &always,
+				})
+				test.Ok(t, err)
+
+				test.Equal(t, f.NoArgValue(), "always")
+				test.Equal(t, f.NoArgDefault(), "always")
+
+				set := flag.NewSet()
+				test.Ok(t, flag.AddToSet(set, f))
+				test.Ok(t, set.Parse(tt.args))
+
+				test.Equal(t, colour, tt.want)
+				test.EqualFunc(t, set.Args(), tt.remain, slices.Equal)
+			})
+		}
+	})
+
+	t.Run("typed", func(t *testing.T) {
+		var level int
+
+		three := 3
+		f, err := flag.New(&level, "level", 'l', "Compression level", flag.Config[int]{NoArgDefault: &three})
+		test.Ok(t, err)
+
+		set := flag.NewSet()
+		test.Ok(t, flag.AddToSet(set, f))
+		test.Ok(t, set.Parse([]string{"--level"}))
+		test.Equal(t, level, 3)
+	})
+
+	t.Run("implied by type", func(t *testing.T) {
+		f, err := flag.New(new(bool), "force", 'f', "Force", flag.Config[bool]{})
+		test.Ok(t, err)
+
+		test.Equal(t, f.NoArgValue(), format.True)
+		test.Equal(t, f.NoArgDefault(), "") // Not explicitly configured
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		empty := ""
+		_, err := flag.New(new(string), "colour", 'c', "Colour", flag.Config[string]{NoArgDefault: &empty})
+		test.Err(t, err)
+		test.Equal(t, err.Error(), `flag "colour": no-arg default must not be empty`)
+
+		items := []string{"a"}
+		_, err = flag.New(new([]string), "items", 'i', "Items", flag.Config[[]string]{NoArgDefault: &items})
+		test.Err(t, err)
+		test.Equal(t, err.Error(), `flag "items": no-arg defaults are not supported for slice flags`)
+
+		always := "always"
+		_, err = flag.New(new(string), "colour", 'c', "Colour", flag.Config[string]{
+			Choices:      []string{"auto", "never"},
+			NoArgDefault: &always,
+		})
+		test.Err(t, err)
+		test.Equal(t, err.Error(), `flag "colour": no-arg default "always" is not one of the choices [auto never]`)
+	})
+}
+
+func TestDelimiter(t *testing.T) {
+	tests := []struct {
+		name   string   // Name of the test case
+		env    string   // Value of $ITEMS, "" for unset
+		errMsg string   // Expected error from Parse, "" for none
+		args   []string // Arguments to parse
+		want   []string // Expected value of the flag
+	}{
+		{
+			name: "single value",
+			args: []string{"--items", "one"},
+			want: []string{"one"},
+		},
+		{
+			name: "delimited",
+			args: []string{"--items", "one;two;three"},
+			want: []string{"one", "two", "three"},
+		},
+		{
+			name: "delimited with equals",
+			args: []string{"--items=one;two", "-i", "three"},
+			want: []string{"one", "two", "three"},
+		},
+		{
+			name: "quoted and escaped",
+			args: []string{"--items", `"a;b";c\;d`},
+			want: []string{"a;b", "c;d"},
+		},
+		{
+			name: "commas not split",
+			args: []string{"--items", "a,b"},
+			want: []string{"a,b"},
+		},
+		{
+			name: "env var",
+			env:  `one;"two;three"`,
+			want: []string{"one", "two;three"},
+		},
+		{
+			name: "windows paths",
+			args: []string{"--items", `C:\dir;D:\x`},
+			want: []string{`C:\dir`, `D:\x`},
+		},
+		{
+			name: "env var windows paths",
+			env:  `C:\dir;D:\x\y`,
+			want: []string{`C:\dir`, `D:\x\y`},
+		},
+		{
+			name:   "unterminated quote",
+			args:   []string{"--items", `"a;b`},
+			errMsg: `flag --items: unterminated quote in "\"a;b"`,
+		},
+		{
+			name:   "env var unterminated quote",
+			env:    `"a`,
+			errMsg: `could not set flag from env: env var ITEMS: unterminated quote in "\"a"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.env != "" {
+				t.Setenv("ITEMS", tt.env)
+			}
+
+			var items []string
+
+			f, err := flag.New(&items, "items", 'i', "Items", flag.Config[[]string]{Delimiter: ";", EnvVar: "ITEMS"})
+			test.Ok(t, err)
+
+			test.Equal(t, f.Delimiter(), ";")
+
+			set := flag.NewSet()
+			test.Ok(t, flag.AddToSet(set, f))
+
+			err = set.Parse(tt.args)
+			if tt.errMsg != "" {
+				test.Err(t, err)
+				test.Equal(t, err.Error(), tt.errMsg)
+
+				return
+			}
+
+			test.Ok(t, err)
+			test.EqualFunc(t, items, tt.want, slices.Equal)
+		})
+	}
+
+	t.Run("not a slice", func(t *testing.T) {
+		_, err := flag.New(new(string), "name", 'n', "Name", flag.Config[string]{Delimiter: ","})
+		test.Err(t, err)
+		test.Equal(t, err.Error(), `flag "name": delimiters are only supported for slice flags`)
+	})
+}
+
+func TestReplaceDefault(t *testing.T) {
+	tests := []struct {
+		name    string   // Name of the test case
+		env     string   // Value of $ITEMS, "" for unset
+		args    []string // Arguments to parse
+		want    []string // Expected value of the flag
+		replace bool     // Whether the flag has ReplaceDefault
+	}{
+		{
+			name:    "not given",
+			replace: true,
+			want:    []string{"a"},
+		},
+		{
+			name:    "appends without option",
+			replace: false,
+			args:    []string{"--items", "b"},
+			want:    []string{"a", "b"},
+		},
+		{
+			name:    "replaces",
+			replace: true,
+			args:    []string{"--items", "b", "-i", "c"},
+			want:    []string{"b", "c"},
+		},
+		{
+			name:    "env replaces",
+			replace: true,
+			env:     "x,y",
+			want:    []string{"x", "y"},
+		},
+		{
+			name:    "command line replaces env",
+			replace: true,
+			env:     "x,y",
+			args:    []string{"--items", "b"},
+			want:    []string{"b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.env != "" {
+				t.Setenv("ITEMS", tt.env)
+			}
+
+			var items []string
+
+			f, err := flag.New(&items, "items", 'i', "Items", flag.Config[[]string]{
+				DefaultValue:   []string{"a"},
+				EnvVar:         "ITEMS",
+				ReplaceDefault: tt.replace,
+			})
+			test.Ok(t, err)
+
+			test.Equal(t, f.ReplaceDefault(), tt.replace)
+
+			set := flag.NewSet()
+			test.Ok(t, flag.AddToSet(set, f))
+
+			test.Ok(t, set.Parse(tt.args))
+			test.EqualFunc(t, items, tt.want, slices.Equal)
+
+			// Parsing again from scratch gives the same result
+			set.Reset()
+			test.Ok(t, set.Parse(tt.args))
+			test.EqualFunc(t, items, tt.want, slices.Equal)
+		})
+	}
+
+	t.Run("not a slice", func(t *testing.T) {
+		_, err := flag.New(new(string), "name", 'n', "Name", flag.Config[string]{ReplaceDefault: true})
+		test.Err(t, err)
+		test.Equal(t, err.Error(), `flag "name": replacing the default is only supported for slice flags`)
+	})
+}
+
 func TestFlagNilSafety(t *testing.T) {
 	t.Run("with new", func(t *testing.T) {
 		// Passing a nil target is an error
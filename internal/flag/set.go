@@ -3,6 +3,7 @@ package flag
 import (
 	"errors"
 	"fmt"
+	"io"
 	"iter"
 	"maps"
 	"os"
@@ -12,15 +13,23 @@ import (
 
 	"go.followtheprocess.codes/cli/flag"
 	"go.followtheprocess.codes/cli/internal/format"
+	"go.followtheprocess.codes/cli/internal/fromfile"
+	"go.followtheprocess.codes/cli/internal/parse"
 )
 
 // Set is a set of command line flags.
 type Set struct {
-	flags      map[string]Value  // The actual stored flags, can lookup by name
-	shorthands map[rune]Value    // The flags by shorthand
-	envVars    map[string]string // flag name → env var name. Lazily created on first flag with an env var
-	args       []string          // Arguments minus flags or flag values
-	extra      []string          // Arguments after "--" was hit
+	flags      map[string]Value    // The actual stored flags, can lookup by name
+	shorthands map[rune]Value      // The flags by shorthand
+	envVars    map[string]string   // flag name → env var name. Lazily created on first flag with an env var
+	groups     []string            // Flag group titles in the order they were first declared
+	args       []string            // Arguments minus flags or flag values
+	extra      []string            // Arguments after "--" was hit
+	stdin      io.Reader           // Where flags read "-" values from, see SetStdin
+	changed    map[string]bool     // Names of flags set by the last Parse, from the command line or env
+	forwarded  map[string][]string // flag name → raw values given to it in the last Parse, for flags with a replacement
+	aliases    map[string]Value    // The flags by alias. Lazily created on first flag with an alias
+	replaced   map[string]bool     // Names of flags with ReplaceDefault cleared on the command line in the last Parse
 }
 
 // typicalFlagCount is a rough guess at the number of flags a single
@@ -53,6 +62,20 @@ func AddToSet[T flag.Flaggable](set *Set, f *Flag[T]) error {
 		return fmt.Errorf("flag %q already defined", name)
 	}
 
+	if existing, exists := set.aliases[name]; exists {
+		return fmt.Errorf("flag %q already defined as an alias of flag %q", name, existing.Name())
+	}
+
+	for _, alias := range f.Aliases() {
+		if _, exists := set.flags[alias]; exists {
+			return fmt.Errorf("alias %q of flag %q already defined as a flag", alias, name)
+		}
+
+		if existing, exists := set.aliases[alias]; exists {
+			return fmt.Errorf("alias %q of flag %q already defined as an alias of flag %q", alias, name, existing.Name())
+		}
+	}
+
 	if short != flag.NoShortHand {
 		existingFlag, exists := set.shorthands[short]
 		if exists {
@@ -62,6 +85,14 @@ func AddToSet[T flag.Flaggable](set *Set, f *Flag[T]) error {
 
 	set.flags[name] = f
 
+	for _, alias := range f.Aliases() {
+		if set.aliases == nil {
+			set.aliases = make(map[string]Value, typicalFlagCount)
+		}
+
+		set.aliases[alias] = f
+	}
+
 	if f.envVar != "" {
 		if set.envVars == nil {
 			set.envVars = make(map[string]string, typicalFlagCount)
@@ -70,6 +101,10 @@ func AddToSet[T flag.Flaggable](set *Set, f *Flag[T]) error {
 		set.envVars[name] = f.envVar
 	}
 
+	if group := f.Group(); group != "" && !f.Hidden() && !slices.Contains(set.groups, group) {
+		set.groups = append(set.groups, group)
+	}
+
 	// Only add the shorthand if it wasn't opted out of
 	if short != flag.NoShortHand {
 		set.shorthands[short] = f
@@ -78,19 +113,20 @@ func AddToSet[T flag.Flaggable](set *Set, f *Flag[T]) error {
 	return nil
 }
 
-// Get gets a flag from the Set by name and a boolean to indicate
-// whether it was present.
+// Get gets a flag from the Set by name, or any of its aliases, and a boolean to
+// indicate whether it was present.
 func (s *Set) Get(name string) (Value, bool) {
 	if s == nil {
 		return nil, false
 	}
 
-	flag, ok := s.flags[name]
-	if !ok {
-		return nil, false
+	if flag, ok := s.flags[name]; ok {
+		return flag, true
 	}
 
-	return flag, true
+	flag, ok := s.aliases[name]
+
+	return flag, ok
 }
 
 // GetShort gets a flag from the Set by it's shorthand and a boolean to indicate
@@ -142,6 +178,16 @@ func (s *Set) Version() (value, ok bool) {
 	return flag.String() == format.True, true
 }
 
+// SetStdin sets the reader that flags configured to read their value from
+// a file or stdin will read from when given "-".
+func (s *Set) SetStdin(stdin io.Reader) {
+	if s == nil {
+		return
+	}
+
+	s.stdin = stdin
+}
+
 // Args returns a slice of all the non-flag arguments, including any
 // following a "--" terminator.
 func (s *Set) Args() []string {
@@ -174,6 +220,9 @@ func (s *Set) Parse(args []string) error {
 	// (e.g. re-executing a Command) don't accumulate args
 	s.args = s.args[:0]
 	s.extra = nil
+	clear(s.changed)
+	clear(s.forwarded)
+	clear(s.replaced)
 
 	if len(s.envVars) > 0 {
 		if err = s.applyEnvVars(); err != nil {
@@ -191,7 +240,7 @@ func (s *Set) Parse(args []string) error {
 			s.args = append(s.args, args...)
 			s.extra = s.args[terminatorIndex:]
 
-			return nil
+			break
 		}
 
 		switch {
@@ -201,8 +250,8 @@ func (s *Set) Parse(args []string) error {
 			if err != nil {
 				return err
 			}
-		case strings.HasPrefix(arg, "-"):
-			// Short flag e.g. -d
+		case strings.HasPrefix(arg, "-") && arg != "-":
+			// Short flag e.g. -d, a lone "-" conventionally means stdin so is positional
 			args, err = s.parseShortFlag(arg, args)
 			if err != nil {
 				return err
@@ -213,9 +262,90 @@ func (s *Set) Parse(args []string) error {
 		}
 	}
 
+	return s.forward()
+}
+
+// CheckReplacements returns an error if any flag is replaced by a flag that doesn't
+// exist in the set, or by itself.
+func (s *Set) CheckReplacements() error {
+	if s == nil {
+		return nil
+	}
+
+	for name, f := range s.Sorted() {
+		replacement := f.ReplacedBy()
+		if replacement == "" {
+			continue
+		}
+
+		target, exists := s.Get(replacement)
+		if !exists {
+			return fmt.Errorf("flag %q is replaced by flag %q which does not exist", name, replacement)
+		}
+
+		if target.Name() == name {
+			return fmt.Errorf("flag %q cannot be replaced by itself", name)
+		}
+	}
+
 	return nil
 }
 
+// Reset restores every flag in the set to its default value and forgets the
+// arguments from any previous Parse, so the set can be parsed again from scratch.
+func (s *Set) Reset() {
+	if s == nil {
+		return
+	}
+
+	for _, f := range s.flags {
+		f.Reset()
+	}
+
+	s.args = s.args[:0]
+	s.extra = nil
+	clear(s.changed)
+	clear(s.forwarded)
+	clear(s.replaced)
+}
+
+// SetPrompted sets the flag called name to value, the answer given to a prompt for it.
+//
+// The value is set just like one from the command line, so the flag is marked as changed
+// and forwarded to any replacement, except that the answer is the whole value of a
+// slice flag, replacing its default rather than being added to it.
+func (s *Set) SetPrompted(name, value string) error {
+	if s == nil {
+		return errors.New("SetPrompted called on a nil set")
+	}
+
+	f, exists := s.Get(name)
+	if !exists {
+		return fmt.Errorf("flag %q does not exist", name)
+	}
+
+	if f.IsSlice() {
+		f.Clear()
+	}
+
+	if err := s.set(f, value); err != nil {
+		return err
+	}
+
+	return s.forward()
+}
+
+// Changed reports whether the flag called name was set by the last call to
+// [Set.Parse], either on the command line or by its environment variable, or
+// since by [Set.SetPrompted].
+func (s *Set) Changed(name string) bool {
+	if s == nil {
+		return false
+	}
+
+	return s.changed[name]
+}
+
 // Sorted returns an iterator through the flags in the flagset
 // in alphabetical order by name.
 func (s *Set) Sorted() iter.Seq2[string, Value] {
@@ -235,6 +365,18 @@ func (s *Set) Sorted() iter.Seq2[string, Value] {
 	}
 }
 
+// Groups returns the titles of any flag groups in the set, in the order
+// in which they were first declared.
+//
+// Flags without a group are not represented here.
+func (s *Set) Groups() []string {
+	if s == nil {
+		return nil
+	}
+
+	return s.groups
+}
+
 // All returns an iterator through the flags in the flagset
 // in alphabetical order by name.
 func (s *Set) All() iter.Seq2[string, Value] {
@@ -260,25 +402,181 @@ func (s *Set) applyEnvVars() error {
 		}
 
 		if f.IsSlice() {
-			for item := range strings.SplitSeq(val, ",") {
-				if item = strings.TrimSpace(item); item != "" {
-					if err := f.Set(item); err != nil {
-						return fmt.Errorf("env var %s: %w", envName, err)
-					}
+			items, err := splitEnv(val, f.Delimiter())
+			if err != nil {
+				return fmt.Errorf("env var %s: %w", envName, redactSplit(f, err))
+			}
+
+			if f.ReplaceDefault() {
+				f.Clear()
+			}
+
+			for _, item := range items {
+				if err := f.Set(item); err != nil {
+					return fmt.Errorf("env var %s: %w", envName, err)
 				}
+
+				s.record(f, item)
 			}
 
+			s.markChanged(name)
+
 			continue
 		}
 
 		if err := f.Set(val); err != nil {
 			return fmt.Errorf("env var %s: %w", envName, err)
 		}
+
+		s.record(f, val)
+		s.markChanged(name)
+	}
+
+	return nil
+}
+
+// splitEnv splits the value of an env var for a slice flag into items. Flags with a
+// delimiter split on it the same way as on the command line, quotes and escapes included,
+// otherwise the value is simply split on commas.
+func splitEnv(value, delimiter string) ([]string, error) {
+	if delimiter != "" {
+		return parse.Split(value, delimiter)
+	}
+
+	var items []string
+
+	for item := range strings.SplitSeq(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items, nil
+}
+
+// redactSplit masks the value in an error from splitting the value of a sensitive
+// flag, the same way [parse.Redact] does for values that fail to parse.
+func redactSplit(flag Value, err error) error {
+	if flag.Sensitive() && errors.Is(err, parse.ErrUnterminatedQuote) {
+		return fmt.Errorf("%w in %q", parse.ErrUnterminatedQuote, parse.Mask)
+	}
+
+	return err
+}
+
+// set sets the value of flag from a value given on the command line, reading
+// it from a file or stdin first if the flag is configured to allow it, and records
+// that the flag has changed.
+func (s *Set) set(flag Value, value string) error {
+	s.replace(flag)
+
+	if !flag.FromFile() {
+		values := []string{value}
+
+		if flag.Delimiter() != "" {
+			items, err := parse.Split(value, flag.Delimiter())
+			if err != nil {
+				return fmt.Errorf("flag --%s: %w", flag.Name(), redactSplit(flag, err))
+			}
+
+			values = items
+		}
+
+		for _, value := range values {
+			if err := flag.Set(value); err != nil {
+				return err
+			}
+
+			s.record(flag, value)
+		}
+
+		s.markChanged(flag.Name())
+
+		return nil
+	}
+
+	values, err := fromfile.Values(value, s.stdin, flag.IsSlice())
+	if err != nil {
+		return fmt.Errorf("flag --%s: %w", flag.Name(), err)
+	}
+
+	for _, value := range values {
+		if err := flag.Set(value); err != nil {
+			return err
+		}
+
+		s.record(flag, value)
+	}
+
+	s.markChanged(flag.Name())
+
+	return nil
+}
+
+// replace clears a flag with ReplaceDefault the first time it's given on the command
+// line during a Parse, so the values given replace its default (or those from its env var)
+// rather than being added to them.
+func (s *Set) replace(flag Value) {
+	if !flag.ReplaceDefault() || s.replaced[flag.Name()] {
+		return
+	}
+
+	if s.replaced == nil {
+		s.replaced = make(map[string]bool)
+	}
+
+	flag.Clear()
+	s.replaced[flag.Name()] = true
+}
+
+// record remembers the raw value given to flag if it has a replacement, so it can be
+// forwarded once parsing is done.
+func (s *Set) record(flag Value, value string) {
+	if flag.ReplacedBy() == "" {
+		return
+	}
+
+	if s.forwarded == nil {
+		s.forwarded = make(map[string][]string)
+	}
+
+	s.forwarded[flag.Name()] = append(s.forwarded[flag.Name()], value)
+}
+
+// forward sets each replacement flag from the values given to the flag it replaces
+// during this Parse. A replacement that was set itself keeps its own value.
+func (s *Set) forward() error {
+	for _, name := range slices.Sorted(maps.Keys(s.forwarded)) {
+		old := s.flags[name]
+		replacement, exists := s.Get(old.ReplacedBy())
+
+		if !exists || s.changed[replacement.Name()] {
+			continue
+		}
+
+		s.replace(replacement)
+
+		for _, value := range s.forwarded[name] {
+			if err := replacement.Set(value); err != nil {
+				return fmt.Errorf("flag --%s (replaced by --%s): %w", name, replacement.Name(), err)
+			}
+		}
+
+		s.markChanged(replacement.Name())
 	}
 
 	return nil
 }
 
+// markChanged records that the flag called name was set during this Parse.
+func (s *Set) markChanged(name string) {
+	if s.changed == nil {
+		s.changed = make(map[string]bool, typicalFlagCount)
+	}
+
+	s.changed[name] = true
+}
+
 // parseLongFlag parses a single long flag e.g. --delete. It is passed
 // the possible long flag and the rest of the argument list and returns
 // the remaining arguments after it's done parsing to the caller.
@@ -291,14 +589,14 @@ func (s *Set) parseLongFlag(long string, rest []string) (remaining []string, err
 	// name will either be the entire string or the name before the "="
 	name, value, containsEquals := strings.Cut(name, "=")
 
-	flag, exists := s.flags[name]
+	flag, exists := s.Get(name)
 	if !exists {
-		return nil, fmt.Errorf("unrecognised flag: --%s", name)
+		return nil, &UnknownFlagError{Name: name}
 	}
 
 	if containsEquals {
 		// Must be "flag=value"
-		err := flag.Set(value)
+		err := s.set(flag, value)
 		if err != nil {
 			return nil, err
 		}
@@ -307,11 +605,19 @@ func (s *Set) parseLongFlag(long string, rest []string) (remaining []string, err
 		return rest, nil
 	}
 
-	// Must now either be --flag (boolean) or --flag value
+	// Must now either be --flag (boolean), --flag value or --flag 3 for a count
 	switch {
+	case flag.NoArgValue() != "" && len(rest) > 0 && flag.TakesNext(rest[0]):
+		// --flag 3 (count)
+		err := s.set(flag, rest[0])
+		if err != nil {
+			return nil, err
+		}
+
+		return rest[1:], nil
 	case flag.NoArgValue() != "":
 		// --flag (boolean)
-		err := flag.Set(flag.NoArgValue())
+		err := s.set(flag, flag.NoArgValue())
 		if err != nil {
 			return nil, err
 		}
@@ -321,7 +627,7 @@ func (s *Set) parseLongFlag(long string, rest []string) (remaining []string, err
 		// --flag value
 		value := rest[0]
 
-		err := flag.Set(value)
+		err := s.set(flag, value)
 		if err != nil {
 			return nil, err
 		}
@@ -367,7 +673,7 @@ func (s *Set) parseSingleShortFlag(shorthands string, rest []string) (string, []
 
 	flag, exists := s.shorthands[char]
 	if !exists {
-		return "", nil, fmt.Errorf("unrecognised shorthand flag: %q in -%s", string(char), shorthands)
+		return "", nil, &UnknownFlagError{Name: string(char), Shorthand: true, group: shorthands}
 	}
 
 	switch {
@@ -375,7 +681,7 @@ func (s *Set) parseSingleShortFlag(shorthands string, rest []string) (string, []
 		// '-f=value' (value may be empty, symmetric with '--flag=')
 		value := shorthands[2:]
 
-		err := flag.Set(value)
+		err := s.set(flag, value)
 		if err != nil {
 			return "", nil, err
 		}
@@ -383,9 +689,18 @@ func (s *Set) parseSingleShortFlag(shorthands string, rest []string) (string, []
 		// Nothing to trim off the arguments as "-f=value" is all 1 arg
 		return "", rest, nil
 
+	case flag.NoArgValue() != "" && len(shorthands) == 1 && len(rest) > 0 && flag.TakesNext(rest[0]):
+		// '-v 3' for a count, only the last of a group of shorthands may take the next argument
+		err := s.set(flag, rest[0])
+		if err != nil {
+			return "", nil, err
+		}
+
+		return "", rest[1:], nil
+
 	case flag.NoArgValue() != "":
 		// -f with implied value e.g. boolean or count
-		err := flag.Set(flag.NoArgValue())
+		err := s.set(flag, flag.NoArgValue())
 		if err != nil {
 			return "", nil, err
 		}
@@ -397,7 +712,7 @@ func (s *Set) parseSingleShortFlag(shorthands string, rest []string) (string, []
 		// '-fvalue'
 		value := shorthands[1:]
 
-		err := flag.Set(value)
+		err := s.set(flag, value)
 		if err != nil {
 			return "", nil, err
 		}
@@ -410,7 +725,7 @@ func (s *Set) parseSingleShortFlag(shorthands string, rest []string) (string, []
 		// '-f value'
 		value := rest[0]
 
-		err := flag.Set(value)
+		err := s.set(flag, value)
 		if err != nil {
 			return "", nil, err
 		}
@@ -424,3 +739,26 @@ func (s *Set) parseSingleShortFlag(shorthands string, rest []string) (string, []
 		return "", nil, fmt.Errorf("flag %s needs an argument: %q in -%s", flag.Name(), string(char), shorthands)
 	}
 }
+
+// UnknownFlagError is the error returned when parsing encounters a flag that
+// has not been defined.
+type UnknownFlagError struct {
+	// Name is the name of the flag as given on the command line, without any
+	// leading hyphens e.g. "force" for --force or "f" for -f.
+	Name string
+
+	// Shorthand is whether the flag was given in its shorthand form e.g. -f.
+	Shorthand bool
+
+	// group is the full run of shorthands the flag was found in e.g. "vfg" in -vfg.
+	group string
+}
+
+// Error implements the error interface for [UnknownFlagError].
+func (e *UnknownFlagError) Error() string {
+	if e.Shorthand {
+		return fmt.Sprintf("unrecognised shorthand flag: %q in -%s", e.Name, e.group)
+	}
+
+	return "unrecognised flag: --" + e.Name
+}
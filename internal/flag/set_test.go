@@ -154,13 +154,16 @@ func TestParse(t *testing.T) {
 			errMsg:  `unrecognised shorthand flag: "u" in -uvalue`,
 		},
 		{
-			name: "bad syntax short empty name",
+			name: "lone hyphen is positional",
 			newSet: func(t *testing.T) *flag.Set {
 				return flag.NewSet()
 			},
+			test: func(t *testing.T, set *flag.Set) {
+				// By convention "-" means stdin, so it's an argument not a flag
+				test.EqualFunc(t, set.Args(), []string{"-"}, slices.Equal)
+			},
 			args:    []string{"-"},
-			wantErr: true,
-			errMsg:  `invalid flag name "": must not be empty`,
+			wantErr: false,
 		},
 		{
 			name: "bad syntax short more than 1 char equals",
@@ -794,6 +797,118 @@ func TestParse(t *testing.T) {
 			args:    []string{"-ccc"},
 			wantErr: false,
 		},
+		{
+			name: "count long with value",
+			newSet: func(t *testing.T) *flag.Set {
+				set := flag.NewSet()
+				f, err := flag.New(
+					new(publicflag.Count),
+					"count",
+					'c',
+					"Count something",
+					flag.Config[publicflag.Count]{},
+				)
+				test.Ok(t, err)
+
+				err = flag.AddToSet(set, f)
+				test.Ok(t, err)
+
+				return set
+			},
+			test: func(t *testing.T, set *flag.Set) {
+				flag, exists := set.Get("count")
+				test.True(t, exists)
+
+				test.Equal(t, flag.String(), "3") // Takes the valid count
+				test.EqualFunc(t, set.Args(), []string{"file.txt"}, slices.Equal)
+			},
+			args:    []string{"--count", "3", "file.txt"},
+			wantErr: false,
+		},
+		{
+			name: "count short with value",
+			newSet: func(t *testing.T) *flag.Set {
+				set := flag.NewSet()
+				f, err := flag.New(
+					new(publicflag.Count),
+					"count",
+					'c',
+					"Count something",
+					flag.Config[publicflag.Count]{},
+				)
+				test.Ok(t, err)
+
+				err = flag.AddToSet(set, f)
+				test.Ok(t, err)
+
+				return set
+			},
+			test: func(t *testing.T, set *flag.Set) {
+				flag, exists := set.Get("count")
+				test.True(t, exists)
+
+				test.Equal(t, flag.String(), "3") // Takes the valid count
+				test.EqualFunc(t, set.Args(), nil, slices.Equal)
+			},
+			args:    []string{"-c", "3"},
+			wantErr: false,
+		},
+		{
+			name: "count short group with value",
+			newSet: func(t *testing.T) *flag.Set {
+				set := flag.NewSet()
+				f, err := flag.New(
+					new(publicflag.Count),
+					"count",
+					'c',
+					"Count something",
+					flag.Config[publicflag.Count]{},
+				)
+				test.Ok(t, err)
+
+				err = flag.AddToSet(set, f)
+				test.Ok(t, err)
+
+				return set
+			},
+			test: func(t *testing.T, set *flag.Set) {
+				flag, exists := set.Get("count")
+				test.True(t, exists)
+
+				test.Equal(t, flag.String(), "4") // Only the last shorthand takes the value
+				test.EqualFunc(t, set.Args(), nil, slices.Equal)
+			},
+			args:    []string{"-cc", "3"},
+			wantErr: false,
+		},
+		{
+			name: "count followed by non count",
+			newSet: func(t *testing.T) *flag.Set {
+				set := flag.NewSet()
+				f, err := flag.New(
+					new(publicflag.Count),
+					"count",
+					'c',
+					"Count something",
+					flag.Config[publicflag.Count]{},
+				)
+				test.Ok(t, err)
+
+				err = flag.AddToSet(set, f)
+				test.Ok(t, err)
+
+				return set
+			},
+			test: func(t *testing.T, set *flag.Set) {
+				flag, exists := set.Get("count")
+				test.True(t, exists)
+
+				test.Equal(t, flag.String(), "2") // Neither argument is a valid count
+				test.EqualFunc(t, set.Args(), []string{"file.txt", "x"}, slices.Equal)
+			},
+			args:    []string{"--count", "file.txt", "-c", "x"},
+			wantErr: false,
+		},
 		{
 			name: "env var applied when flag not set on CLI",
 			newSet: func(t *testing.T) *flag.Set {
@@ -1976,6 +2091,81 @@ func TestParseResetsState(t *testing.T) {
 	})
 }
 
+func TestSetReset(t *testing.T) {
+	set := flag.NewSet()
+
+	var (
+		count int
+		names []string
+	)
+
+	countFlag, err := flag.New(&count, "count", 'c', "Count something", flag.Config[int]{DefaultValue: 3})
+	test.Ok(t, err)
+	test.Ok(t, flag.AddToSet(set, countFlag))
+
+	namesFlag, err := flag.New(&names, "name", 'n', "Names", flag.Config[[]string]{})
+	test.Ok(t, err)
+	test.Ok(t, flag.AddToSet(set, namesFlag))
+
+	test.Ok(t, set.Parse([]string{"--count", "10", "--name", "a", "--name", "b", "pos", "--", "extra"}))
+	test.Equal(t, count, 10)
+	test.EqualFunc(t, names, []string{"a", "b"}, slices.Equal)
+	test.True(t, set.Changed("count"))
+
+	set.Reset()
+
+	test.Equal(t, count, 3)
+	test.Equal(t, len(names), 0)
+	test.Equal(t, len(set.Args()), 0)
+	test.Equal(t, len(set.ExtraArgs()), 0)
+	test.False(t, set.Changed("count"))
+
+	// Slice values must start again, not append to the previous values
+	test.Ok(t, set.Parse([]string{"--name", "c"}))
+	test.EqualFunc(t, names, []string{"c"}, slices.Equal)
+}
+
+func TestSetPrompted(t *testing.T) {
+	set := flag.NewSet()
+
+	var (
+		listen string
+		addr   string
+		names  []string
+	)
+
+	listenFlag, err := flag.New(&listen, "listen", 'l', "Address to listen on", flag.Config[string]{})
+	test.Ok(t, err)
+	test.Ok(t, flag.AddToSet(set, listenFlag))
+
+	addrFlag, err := flag.New(&addr, "addr", publicflag.NoShortHand, "Old address flag", flag.Config[string]{ReplacedBy: "listen"})
+	test.Ok(t, err)
+	test.Ok(t, flag.AddToSet(set, addrFlag))
+
+	namesFlag, err := flag.New(&names, "name", 'n', "Names", flag.Config[[]string]{DefaultValue: []string{"default"}, Delimiter: ","})
+	test.Ok(t, err)
+	test.Ok(t, flag.AddToSet(set, namesFlag))
+
+	test.Ok(t, set.Parse(nil))
+	test.False(t, set.Changed("addr"))
+
+	// Prompted values are set like those from the command line, forwarding included
+	test.Ok(t, set.SetPrompted("addr", ":8080"))
+	test.Equal(t, addr, ":8080")
+	test.Equal(t, listen, ":8080")
+	test.True(t, set.Changed("addr"))
+	test.True(t, set.Changed("listen"))
+
+	// The answer for a slice is its whole value, not added to the default
+	test.Ok(t, set.SetPrompted("name", "a,b"))
+	test.EqualFunc(t, names, []string{"a", "b"}, slices.Equal)
+	test.True(t, set.Changed("name"))
+
+	err = set.SetPrompted("missing", "value")
+	test.Err(t, err)
+	test.Equal(t, err.Error(), `flag "missing" does not exist`)
+}
+
 func TestFlagSet(t *testing.T) {
 	tests := []struct {
 		newSet func(t *testing.T) *flag.Set      // Function to build the flag set under test
@@ -2135,6 +2325,61 @@ func TestFlagSet(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "get by alias",
+			newSet: func(t *testing.T) *flag.Set {
+				set := flag.NewSet()
+
+				f, err := flag.New(new(string), "colour", 'c', "Output colour", flag.Config[string]{Aliases: []string{"color"}})
+				test.Ok(t, err)
+				test.Ok(t, flag.AddToSet(set, f))
+
+				return set
+			},
+			test: func(t *testing.T, set *flag.Set) {
+				f, exists := set.Get("color")
+				test.True(t, exists)
+				test.Equal(t, f.Name(), "colour")
+
+				test.Ok(t, set.Parse([]string{"--color", "red"}))
+				test.Equal(t, f.String(), "red")
+				test.True(t, set.Changed("colour"))
+			},
+		},
+		{
+			name: "alias clashes",
+			newSet: func(t *testing.T) *flag.Set {
+				set := flag.NewSet()
+
+				f, err := flag.New(new(string), "colour", 'c', "Output colour", flag.Config[string]{Aliases: []string{"color"}})
+				test.Ok(t, err)
+				test.Ok(t, flag.AddToSet(set, f))
+
+				return set
+			},
+			test: func(t *testing.T, set *flag.Set) {
+				sameName, err := flag.New(new(bool), "color", publicflag.NoShortHand, "Another", flag.Config[bool]{})
+				test.Ok(t, err)
+
+				err = flag.AddToSet(set, sameName)
+				test.Err(t, err)
+				test.Equal(t, err.Error(), `flag "color" already defined as an alias of flag "colour"`)
+
+				aliasIsFlag, err := flag.New(new(bool), "paint", publicflag.NoShortHand, "Another", flag.Config[bool]{Aliases: []string{"colour"}})
+				test.Ok(t, err)
+
+				err = flag.AddToSet(set, aliasIsFlag)
+				test.Err(t, err)
+				test.Equal(t, err.Error(), `alias "colour" of flag "paint" already defined as a flag`)
+
+				aliasIsAlias, err := flag.New(new(bool), "paint", publicflag.NoShortHand, "Another", flag.Config[bool]{Aliases: []string{"color"}})
+				test.Ok(t, err)
+
+				err = flag.AddToSet(set, aliasIsAlias)
+				test.Err(t, err)
+				test.Equal(t, err.Error(), `alias "color" of flag "paint" already defined as an alias of flag "colour"`)
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -2395,6 +2640,33 @@ func TestSorted(t *testing.T) {
 	}
 }
 
+func TestGroups(t *testing.T) {
+	set := flag.NewSet()
+	test.EqualFunc(t, set.Groups(), nil, slices.Equal)
+
+	port, err := flag.New(new(int), "port", 'p', "Port", flag.Config[int]{Group: "Networking"})
+	test.Ok(t, err)
+
+	config, err := flag.New(new(string), "config", 'c', "Config file", flag.Config[string]{Group: "Configuration"})
+	test.Ok(t, err)
+
+	host, err := flag.New(new(string), "host", 'H', "Host", flag.Config[string]{Group: "Networking"})
+	test.Ok(t, err)
+
+	force, err := flag.New(new(bool), "force", 'f', "Force", flag.Config[bool]{})
+	test.Ok(t, err)
+
+	test.Ok(t, flag.AddToSet(set, port))
+	test.Ok(t, flag.AddToSet(set, config))
+	test.Ok(t, flag.AddToSet(set, host))
+	test.Ok(t, flag.AddToSet(set, force))
+
+	// Declaration order, no duplicates and the ungrouped flag isn't represented
+	test.EqualFunc(t, set.Groups(), []string{"Networking", "Configuration"}, slices.Equal)
+	test.Equal(t, host.Group(), "Networking")
+	test.Equal(t, force.Group(), "")
+}
+
 func TestAll(t *testing.T) {
 	tests := []struct {
 		newSet func(t *testing.T) *flag.Set
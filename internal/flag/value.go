@@ -24,10 +24,58 @@ type Value interface {
 	// or an empty string if none was configured.
 	EnvVar() string
 
+	// Group returns the title of the help section the flag belongs to, or an
+	// empty string if it belongs to the default section.
+	Group() string
+
+	// FromFile reports whether the flag's value may be read from a file ("@path")
+	// or stdin ("-").
+	FromFile() bool
+
+	// Sensitive reports whether the flag's value is masked wherever it would be shown.
+	Sensitive() bool
+
+	// Prompt reports whether the user should be prompted for the flag's value
+	// if it is not otherwise provided.
+	Prompt() bool
+
+	// Required reports whether the flag must be given a value.
+	Required() bool
+
+	// Choices returns the string representations of the values the flag may take,
+	// or nil if it may take any value.
+	Choices() []string
+
+	// Deprecated returns the deprecation message of the flag, or an empty string if
+	// the flag is not deprecated.
+	Deprecated() string
+
+	// ReplacedBy returns the name of the flag values given to this one are forwarded
+	// to, or an empty string if there isn't one.
+	ReplacedBy() string
+
+	// Hidden reports whether the flag is left out of help text and completion.
+	Hidden() bool
+
+	// Aliases returns the other long names the flag may be given by, if any.
+	Aliases() []string
+
+	// Delimiter returns the string that splits each value given to the flag into items,
+	// or an empty string if values aren't split.
+	Delimiter() string
+
 	// NoArgValue returns astring representation of the value of the flag when no
 	// args are passed (e.g --bool implies --bool true).
 	NoArgValue() string
 
+	// TakesNext reports whether the flag, when given on its own, takes arg (the next
+	// argument on the command line) as its value rather than its NoArgValue.
+	TakesNext(arg string) bool
+
+	// NoArgDefault returns the value explicitly configured for the flag to take when
+	// given on its own, or an empty string if there isn't one.
+	NoArgDefault() string
+
 	// Type returns the string representation of the flag type e.g. "bool".
 	Type() string
 
@@ -38,4 +86,14 @@ type Value interface {
 
 	// Set sets the stored value of a flag by parsing the string "str".
 	Set(str string) error
+
+	// Reset restores the flag to its default value.
+	Reset()
+
+	// ReplaceDefault reports whether values given to the flag replace its default
+	// rather than being added to it.
+	ReplaceDefault() bool
+
+	// Clear sets the flag to the zero value of its type.
+	Clear()
 }
@@ -4,7 +4,12 @@
 package format
 
 import (
+	"fmt"
+	"io/fs"
+	"net"
+	"net/url"
 	"strconv"
+	"time"
 	"unsafe"
 
 	"go.followtheprocess.codes/cli/internal/constraints"
@@ -26,6 +31,10 @@ const (
 	floatElemHint  = 8 // "-1.234, "
 	boolElemHint   = 7 // "false, "
 	stringElemHint = 4 // surrounding quotes plus ", "
+
+	stringerElemHint = 16 // e.g. "192.168.0.1, " or "1h30m0s, "
+
+	octal = 8
 )
 
 const (
@@ -35,40 +44,49 @@ const (
 
 // Type names.
 const (
-	TypeInt          = "int"
-	TypeInt8         = "int8"
-	TypeInt16        = "int16"
-	TypeInt32        = "int32"
-	TypeInt64        = "int64"
-	TypeUint         = "uint"
-	TypeCount        = "count"
-	TypeUint8        = "uint8"
-	TypeUint16       = "uint16"
-	TypeUint32       = "uint32"
-	TypeUint64       = "uint64"
-	TypeUintptr      = "uintptr"
-	TypeFloat32      = "float32"
-	TypeFloat64      = "float64"
-	TypeString       = "string"
-	TypeURL          = "url"
-	TypeBool         = "bool"
-	TypeBytesHex     = "bytesHex"
-	TypeTime         = "time"
-	TypeDuration     = "duration"
-	TypeIP           = "ip"
-	TypeIntSlice     = slice + TypeInt
-	TypeInt8Slice    = slice + TypeInt8
-	TypeInt16Slice   = slice + TypeInt16
-	TypeInt32Slice   = slice + TypeInt32
-	TypeInt64Slice   = slice + TypeInt64
-	TypeUintSlice    = slice + TypeUint
-	TypeUint16Slice  = slice + TypeUint16
-	TypeUint32Slice  = slice + TypeUint32
-	TypeUint64Slice  = slice + TypeUint64
-	TypeFloat32Slice = slice + TypeFloat32
-	TypeFloat64Slice = slice + TypeFloat64
-	TypeStringSlice  = slice + TypeString
-	TypeURLSlice     = slice + TypeURL
+	TypeInt           = "int"
+	TypeInt8          = "int8"
+	TypeInt16         = "int16"
+	TypeInt32         = "int32"
+	TypeInt64         = "int64"
+	TypeUint          = "uint"
+	TypeCount         = "count"
+	TypeUint8         = "uint8"
+	TypeUint16        = "uint16"
+	TypeUint32        = "uint32"
+	TypeUint64        = "uint64"
+	TypeUintptr       = "uintptr"
+	TypeFloat32       = "float32"
+	TypeFloat64       = "float64"
+	TypeString        = "string"
+	TypeURL           = "url"
+	TypeBool          = "bool"
+	TypeBytesHex      = "bytesHex"
+	TypeTime          = "time"
+	TypeDuration      = "duration"
+	TypeIP            = "ip"
+	TypeAddr          = "addr"
+	TypePrefix        = "prefix"
+	TypeAddrPort      = "addrPort"
+	TypeRegexp        = "regexp"
+	TypeFileMode      = "fileMode"
+	TypeByteSize      = "byteSize"
+	TypeIntSlice      = slice + TypeInt
+	TypeInt8Slice     = slice + TypeInt8
+	TypeInt16Slice    = slice + TypeInt16
+	TypeInt32Slice    = slice + TypeInt32
+	TypeInt64Slice    = slice + TypeInt64
+	TypeUintSlice     = slice + TypeUint
+	TypeUint16Slice   = slice + TypeUint16
+	TypeUint32Slice   = slice + TypeUint32
+	TypeUint64Slice   = slice + TypeUint64
+	TypeFloat32Slice  = slice + TypeFloat32
+	TypeFloat64Slice  = slice + TypeFloat64
+	TypeStringSlice   = slice + TypeString
+	TypeURLSlice      = slice + TypeURL
+	TypeBoolSlice     = slice + TypeBool
+	TypeDurationSlice = slice + TypeDuration
+	TypeIPSlice       = slice + TypeIP
 )
 
 // True is the literal boolean true as a string.
@@ -77,6 +95,9 @@ const (
 // default values and when flags have a NoArgValue.
 const True = "true"
 
+// False is the literal boolean false as a string.
+const False = "false"
+
 // Nil is the string representation of a Go nil value.
 const Nil = "<nil>"
 
@@ -139,11 +160,28 @@ func Slice[T any](s []T) string {
 		return formatFloat32Slice(v)
 	case []float64:
 		return formatFloat64Slice(v)
+	case []time.Duration:
+		return formatStringerSlice(v)
+	case []net.IP:
+		return formatStringerSlice(v)
+	case []*url.URL:
+		return formatStringerSlice(v)
 	default:
 		return slice
 	}
 }
 
+// FileMode returns a string representation of the permission bits of a file mode,
+// in octal with a leading 0 e.g. "0755".
+func FileMode(mode fs.FileMode) string {
+	perm := uint64(mode.Perm())
+	if perm == 0 {
+		return "0"
+	}
+
+	return "0" + strconv.FormatUint(perm, octal)
+}
+
 // toString casts b to a string by reinterpreting the bytes.
 //
 // This is the same trick [strings.Builder.String] uses to avoid the
@@ -236,6 +274,21 @@ func formatStringSlice(s []string) string {
 	return toString(buf)
 }
 
+func formatStringerSlice[T fmt.Stringer](s []T) string {
+	buf := make([]byte, 0, bracketsCap+len(s)*stringerElemHint)
+	buf = append(buf, '[')
+	buf = append(buf, s[0].String()...)
+
+	for _, e := range s[1:] {
+		buf = append(buf, ", "...)
+		buf = append(buf, e.String()...)
+	}
+
+	buf = append(buf, ']')
+
+	return toString(buf)
+}
+
 func formatBoolSlice(s []bool) string {
 	buf := make([]byte, 0, bracketsCap+len(s)*boolElemHint)
 	buf = append(buf, '[')
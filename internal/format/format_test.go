@@ -1,9 +1,13 @@
 package format //nolint:testpackage // I need the base and bits values and don't want to export them.
 
 import (
+	"io/fs"
+	"net"
+	"net/url"
 	"strconv"
 	"testing"
 	"testing/quick"
+	"time"
 
 	"go.followtheprocess.codes/test"
 )
@@ -173,6 +177,23 @@ func TestSlice(t *testing.T) {
 			got:  func() string { return Slice([]bool{true, true, false}) },
 			want: "[true, true, false]",
 		},
+		{
+			name: "durations",
+			got:  func() string { return Slice([]time.Duration{time.Second, 90 * time.Minute}) },
+			want: "[1s, 1h30m0s]",
+		},
+		{
+			name: "ips",
+			got:  func() string { return Slice([]net.IP{net.IPv4(192, 0, 2, 1), net.IPv6loopback}) },
+			want: "[192.0.2.1, ::1]",
+		},
+		{
+			name: "urls",
+			got: func() string {
+				return Slice([]*url.URL{{Scheme: "https", Host: "example.com"}, {Scheme: "http", Host: "localhost:8080"}})
+			},
+			want: "[https://example.com, http://localhost:8080]",
+		},
 		{
 			name: "unsupported type falls through to empty",
 			got:  func() string { return Slice([]uintptr{1, 2, 3}) },
@@ -187,6 +208,25 @@ func TestSlice(t *testing.T) {
 	}
 }
 
+func TestFileMode(t *testing.T) {
+	tests := []struct {
+		name string      // Name of the test case
+		mode fs.FileMode // Mode to format
+		want string      // Expected string
+	}{
+		{name: "zero", mode: 0, want: "0"},
+		{name: "executable", mode: 0o755, want: "0755"},
+		{name: "owner only", mode: 0o600, want: "0600"},
+		{name: "type bits ignored", mode: fs.ModeDir | 0o750, want: "0750"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			test.Equal(t, FileMode(tt.mode), tt.want)
+		})
+	}
+}
+
 func BenchmarkSlice(b *testing.B) {
 	ints := []int{1, 2, 3, 4, 5, 6, 7, 8}
 	int64s := []int64{1, 2, 3, 4, 5, 6, 7, 8}
@@ -0,0 +1,66 @@
+// Package fromfile implements reading flag and argument values from files or stdin
+// rather than directly from the command line.
+//
+// It's primarily intended for secrets and large payloads that shouldn't (or can't)
+// be passed as a command line argument.
+package fromfile
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Stdin is the value that means "read from stdin".
+const Stdin = "-"
+
+// Values resolves the raw command line value into the value(s) it refers to.
+//
+// A value of the form "@path" is replaced by the contents of the file at path and
+// [Stdin] by the contents of stdin, any other value is returned unchanged, as is
+// a lone "@".
+//
+// For slice flags, each non-empty line of the contents is a separate value. Otherwise
+// the whole of the contents is a single value with any trailing newlines trimmed off,
+// so files written by editors or 'echo' work as expected.
+func Values(value string, stdin io.Reader, slice bool) ([]string, error) {
+	var (
+		contents []byte
+		err      error
+	)
+
+	switch path, isFile := strings.CutPrefix(value, "@"); {
+	case value == Stdin:
+		if stdin == nil {
+			return nil, errors.New("cannot read value from stdin: stdin is nil")
+		}
+
+		contents, err = io.ReadAll(stdin)
+		if err != nil {
+			return nil, fmt.Errorf("could not read value from stdin: %w", err)
+		}
+	case isFile && path != "":
+		contents, err = os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("could not read value from file: %w", err)
+		}
+	default:
+		return []string{value}, nil
+	}
+
+	if !slice {
+		return []string{strings.TrimRight(string(contents), "\r\n")}, nil
+	}
+
+	var values []string
+
+	for line := range strings.Lines(string(contents)) {
+		if line = strings.TrimRight(line, "\r\n"); line != "" {
+			values = append(values, line)
+		}
+	}
+
+	return values, nil
+}
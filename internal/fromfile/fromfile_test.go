@@ -0,0 +1,79 @@
+package fromfile_test
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"go.followtheprocess.codes/cli/internal/fromfile"
+	"go.followtheprocess.codes/test"
+)
+
+func TestValues(t *testing.T) {
+	dir := t.TempDir()
+
+	secret := filepath.Join(dir, "secret")
+	test.Ok(t, os.WriteFile(secret, []byte("hunter2\n\n"), 0o600))
+
+	lines := filepath.Join(dir, "lines")
+	test.Ok(t, os.WriteFile(lines, []byte("one\r\ntwo\n\nthree"), 0o600))
+
+	tests := []struct {
+		name    string   // Name of the test case
+		value   string   // Raw value from the command line
+		stdin   string   // Contents of stdin
+		want    []string // Expected values
+		slice   bool     // Whether the target is a slice
+		wantErr bool     // Whether we want an error
+	}{
+		{
+			name:  "literal",
+			value: "hello",
+			want:  []string{"hello"},
+		},
+		{
+			name:  "lone at",
+			value: "@",
+			want:  []string{"@"},
+		},
+		{
+			name:  "file",
+			value: "@" + secret,
+			want:  []string{"hunter2"},
+		},
+		{
+			name:  "stdin",
+			value: "-",
+			stdin: "from stdin\r\n",
+			want:  []string{"from stdin"},
+		},
+		{
+			name:  "file slice",
+			value: "@" + lines,
+			slice: true,
+			want:  []string{"one", "two", "three"},
+		},
+		{
+			name:  "stdin slice",
+			value: "-",
+			stdin: "a\nb\n",
+			slice: true,
+			want:  []string{"a", "b"},
+		},
+		{
+			name:    "missing file",
+			value:   "@" + filepath.Join(dir, "missing"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := fromfile.Values(tt.value, strings.NewReader(tt.stdin), tt.slice)
+			test.WantErr(t, err, tt.wantErr)
+			test.EqualFunc(t, got, tt.want, slices.Equal)
+		})
+	}
+}
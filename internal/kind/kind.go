@@ -33,6 +33,12 @@ const (
 	Duration
 	IP
 	URL
+	Addr
+	Prefix
+	AddrPort
+	Regexp
+	FileMode
+	ByteSize
 	IntSlice
 	Int8Slice
 	Int16Slice
@@ -45,4 +51,8 @@ const (
 	Float32Slice
 	Float64Slice
 	StringSlice
+	BoolSlice
+	DurationSlice
+	IPSlice
+	URLSlice
 )
@@ -0,0 +1,35 @@
+// Package override lets other packages within cli (namely clitest) reconfigure a
+// [cli.Command] after it has been built, without adding a way to do so to the public
+// API where it would break the guarantee that commands can only be configured in cli.New.
+//
+// Package cli installs the hooks at init time, so they are always non-nil
+// to anything that imports cli.
+package override
+
+import "io"
+
+// Config is the set of things about a root command that may be overridden.
+type Config struct {
+	Stdin  io.Reader // Replaces the command's stdin
+	Stdout io.Writer // Replaces the command's stdout
+	Stderr io.Writer // Replaces the command's stderr
+	Args   []string  // Replaces the command's raw arguments
+
+	Interactive bool // Treats stdin as interactive, see cli.Interactive
+}
+
+// Hooks installed by package cli.
+//
+// The command is passed as an any because this package cannot import cli
+// without an import cycle, it must be a *cli.Command.
+//
+//nolint:gochecknoglobals // Set exactly once by package cli at init
+var (
+	// Apply applies the config to the root command cmd.
+	Apply func(cmd any, cfg Config) error
+
+	// EnvVars returns the names of every environment variable read by any flag
+	// in the command tree rooted at cmd, along with those that control cli itself
+	// e.g. the pager.
+	EnvVars func(cmd any) []string
+)
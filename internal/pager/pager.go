@@ -0,0 +1,148 @@
+// Package pager implements piping long output through an external terminal
+// pager such as less, in the same way tools like git do.
+package pager
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+const (
+	// Default is the pager used when the user has not configured one.
+	Default = "less -R"
+
+	// noPager is the environment variable that, when set to anything non-empty,
+	// disables paging entirely.
+	noPager = "NO_PAGER"
+
+	// pagerEnv is the environment variable holding the user's preferred pager for
+	// any tool.
+	pagerEnv = "PAGER"
+
+	// lessEnv is the environment variable less reads its default options from.
+	lessEnv = "LESS"
+
+	// lessDefaults are the options passed to less via $LESS if the user has not
+	// set their own. F quits if the output fits on one screen, R passes through
+	// colour escape codes and X stops less clearing the screen on exit.
+	lessDefaults = "FRX"
+)
+
+// EnvVar returns the name of the tool specific environment variable that may be
+// used to override the pager for a command called name.
+//
+//	EnvVar("mytool") // "MYTOOL_PAGER"
+//	EnvVar("my-tool") // "MY_TOOL_PAGER"
+func EnvVar(name string) string {
+	return strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_PAGER"
+}
+
+// EnvVars returns the names of every environment variable that configures paging for
+// a command called name.
+func EnvVars(name string) []string {
+	return []string{EnvVar(name), noPager, pagerEnv}
+}
+
+// Command resolves the pager command line to use, returning it split into
+// the program and its arguments along with a boolean indicating whether paging
+// is enabled at all.
+//
+// The tool specific variable named by envVar takes precedence, followed by $PAGER
+// and finally [Default]. Setting $NO_PAGER, or setting the pager to an empty string
+// or "cat", disables paging.
+func Command(envVar string) (argv []string, ok bool) {
+	if os.Getenv(noPager) != "" {
+		return nil, false
+	}
+
+	pager := Default
+
+	if value, set := os.LookupEnv(envVar); set {
+		pager = value
+	} else if value, set := os.LookupEnv(pagerEnv); set {
+		pager = value
+	}
+
+	argv = strings.Fields(pager)
+	if len(argv) == 0 || argv[0] == "cat" {
+		return nil, false
+	}
+
+	return argv, true
+}
+
+// Height reports the height in lines of the terminal w is connected to, and
+// whether w is a terminal at all.
+func Height(w io.Writer) (height int, ok bool) {
+	f, ok := w.(*os.File)
+	if !ok {
+		return 0, false
+	}
+
+	fd := int(f.Fd())
+	if !term.IsTerminal(fd) {
+		return 0, false
+	}
+
+	_, height, err := term.GetSize(fd)
+	if err != nil {
+		return 0, false
+	}
+
+	return height, true
+}
+
+// Writer is an [io.WriteCloser] that feeds everything written to it into
+// a running pager process.
+//
+// Close must be called once all output has been written, it waits for the user
+// to exit the pager.
+type Writer struct {
+	stdin io.WriteCloser
+	cmd   *exec.Cmd
+}
+
+// Start launches the pager described by argv, with its output going to w.
+func Start(argv []string, w io.Writer) (*Writer, error) {
+	if len(argv) == 0 {
+		return nil, errors.New("no pager command given")
+	}
+
+	cmd := exec.Command(argv[0], argv[1:]...) //nolint:gosec // Running the user's configured pager is the whole point
+	cmd.Stdout = w
+	cmd.Stderr = w
+
+	if _, set := os.LookupEnv(lessEnv); !set {
+		cmd.Env = append(os.Environ(), lessEnv+"="+lessDefaults)
+	}
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("could not connect to pager stdin: %w", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("could not start pager %q: %w", argv[0], err)
+	}
+
+	return &Writer{stdin: stdin, cmd: cmd}, nil
+}
+
+// Write writes p to the pager.
+func (w *Writer) Write(p []byte) (int, error) {
+	return w.stdin.Write(p)
+}
+
+// Close closes the pager's input and waits for it to exit.
+func (w *Writer) Close() error {
+	closeErr := w.stdin.Close()
+	waitErr := w.cmd.Wait()
+
+	return errors.Join(closeErr, waitErr)
+}
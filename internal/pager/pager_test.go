@@ -0,0 +1,115 @@
+package pager_test
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"go.followtheprocess.codes/cli/internal/pager"
+	"go.followtheprocess.codes/test"
+)
+
+func TestEnvVar(t *testing.T) {
+	test.Equal(t, pager.EnvVar("mytool"), "MYTOOL_PAGER")
+	test.Equal(t, pager.EnvVar("my-tool"), "MY_TOOL_PAGER")
+}
+
+func TestCommand(t *testing.T) {
+	tests := []struct {
+		env  map[string]string // Environment variables to set
+		name string            // Name of the test case
+		want []string          // Expected pager command
+		ok   bool              // Expected ok
+	}{
+		{
+			name: "default",
+			env:  map[string]string{},
+			want: []string{"less", "-R"},
+			ok:   true,
+		},
+		{
+			name: "PAGER",
+			env:  map[string]string{"PAGER": "more"},
+			want: []string{"more"},
+			ok:   true,
+		},
+		{
+			name: "tool specific wins",
+			env:  map[string]string{"PAGER": "more", "MYTOOL_PAGER": "most -s"},
+			want: []string{"most", "-s"},
+			ok:   true,
+		},
+		{
+			name: "NO_PAGER",
+			env:  map[string]string{"NO_PAGER": "1", "MYTOOL_PAGER": "most"},
+			want: nil,
+			ok:   false,
+		},
+		{
+			name: "empty disables",
+			env:  map[string]string{"MYTOOL_PAGER": ""},
+			want: nil,
+			ok:   false,
+		},
+		{
+			name: "cat disables",
+			env:  map[string]string{"PAGER": "cat"},
+			want: nil,
+			ok:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{"NO_PAGER", "PAGER", "MYTOOL_PAGER"} {
+				t.Setenv(key, "")
+				os.Unsetenv(key)
+			}
+
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			got, ok := pager.Command("MYTOOL_PAGER")
+			test.Equal(t, ok, tt.ok)
+			test.EqualFunc(t, got, tt.want, slices.Equal)
+		})
+	}
+}
+
+func TestHeight(t *testing.T) {
+	// Not a file
+	_, ok := pager.Height(&bytes.Buffer{})
+	test.False(t, ok)
+
+	// A file, but not a terminal
+	f, err := os.Create(filepath.Join(t.TempDir(), "out.txt"))
+	test.Ok(t, err)
+
+	defer f.Close()
+
+	_, ok = pager.Height(f)
+	test.False(t, ok)
+}
+
+func TestStart(t *testing.T) {
+	out := &bytes.Buffer{}
+
+	w, err := pager.Start([]string{"cat"}, out)
+	test.Ok(t, err)
+
+	_, err = io.WriteString(w, "Some long\noutput\n")
+	test.Ok(t, err)
+
+	test.Ok(t, w.Close())
+	test.Equal(t, out.String(), "Some long\noutput\n")
+
+	_, err = pager.Start(nil, out)
+	test.Err(t, err)
+
+	_, err = pager.Start([]string{"definitely-not-a-real-pager"}, out)
+	test.Err(t, err)
+}
@@ -7,8 +7,16 @@ package parse
 import (
 	"errors"
 	"fmt"
+	"io/fs"
+	"math"
 	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
 	"unsafe"
+
+	"go.followtheprocess.codes/cli/flag"
 )
 
 // Kind is the kind of parsing being done, either argument or flag.
@@ -16,10 +24,14 @@ type Kind string
 
 // Err is a generic parse error.
 //
-// Errors returned from the [Error] function will match this in a call
-// to [errors.Is].
+// Errors returned from the [Error] and [ErrorSlice] functions will match this
+// in a call to [errors.Is].
 var Err = errors.New("parse error")
 
+// ErrUnterminatedQuote is the error returned from [Split] when a double quote is
+// never closed.
+var ErrUnterminatedQuote = errors.New("unterminated quote")
+
 const (
 	// KindArgument is the [Kind] used for argument parsing.
 	KindArgument Kind = "argument"
@@ -35,9 +47,84 @@ const (
 	bits64             // 64 bit integer
 )
 
-const base10 = 10
+const (
+	base10   = 10
+	octal    = 8
+	bitsPerm = 9 // File permission bits, up to 0777
+)
+
+// Mask is shown in place of the value of a sensitive flag or argument.
+const Mask = "******"
+
+// InvalidValueError is the error returned when the value given to a flag or
+// argument cannot be parsed into its type.
+//
+// It matches [Err] in a call to [errors.Is], and the underlying error (e.g. from strconv)
+// is also available to [errors.Is] and [errors.As].
+type InvalidValueError struct {
+	// Err is the underlying error that caused the value to be rejected,
+	// e.g. [strconv.ErrSyntax].
+	Err error
+
+	// Kind is the kind of thing being parsed, either "flag" or "argument".
+	Kind Kind
+
+	// Name is the name of the flag or argument.
+	Name string
+
+	// Value is the raw text that was rejected.
+	Value string
+
+	// Type is the Go type the value was being parsed into e.g. "int" or "[]string".
+	Type string
+
+	// element is whether Value was an element being appended to a slice.
+	element bool
+
+	// redacted is whether the value was sensitive and has been masked, see [Redact].
+	redacted bool
+}
+
+// Error implements the error interface for [InvalidValueError].
+func (e *InvalidValueError) Error() string {
+	if e.redacted {
+		// The underlying error very often quotes the value (e.g. strconv), so leave it out
+		return fmt.Sprintf("%s: %s %q received invalid value %q (expected %s)", Err, e.Kind, e.Name, e.Value, e.Type)
+	}
+
+	if e.element {
+		return fmt.Sprintf("%s: %s %q (type %s) cannot append element %q: %s", Err, e.Kind, e.Name, e.Type, e.Value, e.Err)
+	}
+
+	return fmt.Sprintf("%s: %s %q received invalid value %q (expected %s): %s", Err, e.Kind, e.Name, e.Value, e.Type, e.Err)
+}
+
+// Unwrap returns both [Err] and the underlying error so either may be
+// matched with [errors.Is] and [errors.As].
+func (e *InvalidValueError) Unwrap() []error {
+	return []error{Err, e.Err}
+}
+
+// Redact masks the rejected value in err if it is (or wraps) an [*InvalidValueError],
+// so that sensitive values (e.g. API keys) are never shown in error messages.
+//
+// The underlying error is left out of the message as it will often include the
+// value too, but it may still be matched with [errors.Is] and [errors.As]. Any other
+// error is returned unchanged.
+func Redact(err error) error {
+	var invalid *InvalidValueError
+	if !errors.As(err, &invalid) {
+		return err
+	}
+
+	redacted := *invalid
+	redacted.Value = Mask
+	redacted.redacted = true
+
+	return &redacted
+}
 
-// Error produces a formatted parse error.
+// Error produces a formatted parse error, in the form of an [*InvalidValueError].
 //
 // The kind must be [KindArgument] or [KindFlag], with name and str being the
 // name of the arg/flag and the invalid text that triggered the error.
@@ -53,10 +140,17 @@ func Error[T any](kind Kind, name, str string, typ T, err error) error {
 	// details of other packages (like flag/arg) but given this package exists to produce consistent
 	// behaviour and clear error messages in the narrow context of this cli framework, then
 	// it makes sense the error is defined here too.
-	return fmt.Errorf("%w: %s %q received invalid value %q (expected %T): %w", Err, kind, name, str, typ, err)
+	return &InvalidValueError{
+		Err:   err,
+		Kind:  kind,
+		Name:  name,
+		Value: str,
+		Type:  fmt.Sprintf("%T", typ),
+	}
 }
 
-// ErrorSlice produces a formatted parse error for a slice type.
+// ErrorSlice produces a formatted parse error for a slice type, in the form
+// of an [*InvalidValueError].
 //
 // The kind should must be [KindArgument] or [KindFlag], with name and str being the
 // name of the arg/flag and the invalid text that triggered the error.
@@ -64,7 +158,14 @@ func Error[T any](kind Kind, name, str string, typ T, err error) error {
 // The type T is the type we were parsing str into and err is any underlying
 // error e.g. from strconv.
 func ErrorSlice[T any](kind Kind, name, str string, typ T, err error) error {
-	return fmt.Errorf("%w: %s %q (type %T) cannot append element %q: %w", Err, kind, name, typ, str, err)
+	return &InvalidValueError{
+		Err:     err,
+		Kind:    kind,
+		Name:    name,
+		Value:   str,
+		Type:    fmt.Sprintf("%T", typ),
+		element: true,
+	}
 }
 
 // Int parses an int from a string.
@@ -187,6 +288,203 @@ func Float64(str string) (float64, error) {
 	return float64(val), nil
 }
 
+// Time parses a time from a string, in RFC3339 format or as a date and time
+// (time.DateTime) or just a date (time.DateOnly), both of which are taken to be UTC.
+//
+// If str is in none of them, the error is from parsing it as RFC3339.
+func Time(str string) (time.Time, error) {
+	val, err := time.Parse(time.RFC3339, str)
+	if err == nil {
+		return val, nil
+	}
+
+	for _, layout := range []string{time.DateTime, time.DateOnly} {
+		if val, other := time.Parse(layout, str); other == nil {
+			return val, nil
+		}
+	}
+
+	return time.Time{}, err
+}
+
+// FileMode parses file permissions from an octal string e.g. "0755", "755" or "0o755".
+func FileMode(str string) (fs.FileMode, error) {
+	digits := str
+	if rest, ok := strings.CutPrefix(str, "0o"); ok {
+		digits = rest
+	} else if rest, ok := strings.CutPrefix(str, "0O"); ok {
+		digits = rest
+	}
+
+	val, err := strconv.ParseUint(digits, octal, bitsPerm)
+	if err != nil {
+		var numErr *strconv.NumError
+		if errors.As(err, &numErr) && errors.Is(numErr.Err, strconv.ErrRange) {
+			return 0, errors.New("permissions must be at most 0777")
+		}
+
+		return 0, err
+	}
+
+	return fs.FileMode(val), nil
+}
+
+// ByteSize parses a number of bytes from a string, with an optional unit which
+// may be decimal (KB, MB, GB, TB, PB) or binary (KiB, MiB, GiB, TiB, PiB) e.g. "10MiB" or
+// "1.5GB". Units are case insensitive and a number without one is in bytes.
+func ByteSize(str string) (flag.ByteSize, error) {
+	str = strings.TrimSpace(str)
+	number := strings.TrimRightFunc(str, unicode.IsLetter)
+	unit := str[len(number):]
+	number = strings.TrimSpace(number)
+
+	size, err := byteUnit(unit)
+	if err != nil {
+		return 0, err
+	}
+
+	// Whole numbers are parsed exactly, only fractions go via float
+	if n, err := strconv.ParseUint(number, base10, bits64); err == nil {
+		if n > math.MaxUint64/uint64(size) {
+			return 0, strconv.ErrRange
+		}
+
+		return flag.ByteSize(n) * size, nil
+	}
+
+	val, err := strconv.ParseFloat(number, bits64)
+	if err != nil {
+		return 0, err
+	}
+
+	bytes := val * float64(size)
+
+	switch {
+	case val < 0:
+		return 0, errors.New("must not be negative")
+	case bytes >= math.MaxUint64:
+		return 0, strconv.ErrRange
+	case bytes != math.Trunc(bytes):
+		return 0, errors.New("not a whole number of bytes")
+	}
+
+	return flag.ByteSize(bytes), nil
+}
+
+// byteUnit returns the size of the byte size unit called name, case insensitively.
+// An empty name is bytes.
+func byteUnit(name string) (flag.ByteSize, error) {
+	if name == "" || strings.EqualFold(name, "B") {
+		return flag.Byte, nil
+	}
+
+	for _, unit := range byteUnits {
+		if strings.EqualFold(unit.name, name) {
+			return unit.size, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown unit %q", name)
+}
+
+// byteUnits are the units a byte size may be given in.
+//
+//nolint:gochecknoglobals // Effectively a constant
+var byteUnits = []struct {
+	name string
+	size flag.ByteSize
+}{
+	{"KB", flag.KB}, {"MB", flag.MB}, {"GB", flag.GB}, {"TB", flag.TB}, {"PB", flag.PB},
+	{"KiB", flag.KiB}, {"MiB", flag.MiB}, {"GiB", flag.GiB}, {"TiB", flag.TiB}, {"PiB", flag.PiB},
+}
+
+// Split splits str into items separated by delimiter, for slice values given in a
+// single string e.g. "one,two,three".
+//
+// Items may be wrapped in double quotes or have a delimiter escaped with a backslash to
+// include it in a value e.g. `"a,b",c` and `a\,b,c` are both the two items "a,b" and "c".
+// A backslash only escapes the delimiter, a double quote or another backslash, anywhere
+// else it's kept as it is so Windows paths and regular expressions come through intact.
+// Unquoted items have surrounding whitespace trimmed and are dropped if that leaves them
+// empty, whereas quoted items are kept exactly.
+func Split(str, delimiter string) ([]string, error) {
+	var (
+		items   []string
+		item    strings.Builder
+		quoting bool // Whether we're inside double quotes
+		quoted  bool // Whether the current item had any quotes
+	)
+
+	// flush ends the current item
+	flush := func() {
+		value := item.String()
+		if !quoted {
+			value = strings.TrimSpace(value)
+		}
+
+		if value != "" || quoted {
+			items = append(items, value)
+		}
+
+		item.Reset()
+
+		quoted = false
+	}
+
+	for i := 0; i < len(str); {
+		switch {
+		case str[i] == '\\' && strings.HasPrefix(str[i+1:], delimiter):
+			// Escaped delimiter, taken literally
+			item.WriteString(delimiter)
+
+			i += 1 + len(delimiter)
+		case str[i] == '\\' && i+1 < len(str) && (str[i+1] == '"' || str[i+1] == '\\'):
+			// Escaped quote or backslash
+			item.WriteByte(str[i+1])
+
+			i += 2
+		case str[i] == '"':
+			quoting = !quoting
+			quoted = true
+			i++
+		case !quoting && strings.HasPrefix(str[i:], delimiter):
+			flush()
+
+			i += len(delimiter)
+		default:
+			char, size := utf8.DecodeRuneInString(str[i:])
+			item.WriteRune(char)
+
+			i += size
+		}
+	}
+
+	if quoting {
+		return nil, fmt.Errorf("%w in %q", ErrUnterminatedQuote, str)
+	}
+
+	flush()
+
+	return items, nil
+}
+
+// Join is the inverse of [Split], it joins items with delimiter, quoting any that
+// wouldn't otherwise come back intact: empty items, those with surrounding whitespace
+// and those containing a quote, a backslash or any character of the delimiter.
+func Join(items []string, delimiter string) string {
+	quoted := make([]string, 0, len(items))
+
+	for _, item := range items {
+		if item == "" || item != strings.TrimSpace(item) || strings.ContainsAny(item, `"\`+delimiter) {
+			item = `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(item) + `"`
+		}
+
+		quoted = append(quoted, item)
+	}
+
+	return strings.Join(quoted, delimiter)
+}
+
 // Cast converts a *T1 to a *T2, we use it here when we know (via generics and compile time checks)
 // that e.g. the Flag.value is a string, but we can't directly do Flag.value = "value" because
 // we can't assign a string to a generic 'T', but we *know* that the value *is* a string because when
@@ -2,10 +2,16 @@ package parse //nolint:testpackage // I need the base and bits values and don't
 
 import (
 	"errors"
+	"fmt"
+	"io/fs"
+	"slices"
 	"strconv"
+	"strings"
 	"testing"
 	"testing/quick"
+	"time"
 
+	"go.followtheprocess.codes/cli/flag"
 	"go.followtheprocess.codes/test"
 )
 
@@ -230,6 +236,269 @@ func TestFloat64(t *testing.T) {
 	}
 }
 
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		name      string   // Name of the test case
+		str       string   // Input string
+		delimiter string   // Delimiter to split on
+		errMsg    string   // Expected error, "" for none
+		want      []string // Expected items
+	}{
+		{
+			name:      "empty",
+			str:       "",
+			delimiter: ",",
+			want:      nil,
+		},
+		{
+			name:      "single",
+			str:       "one",
+			delimiter: ",",
+			want:      []string{"one"},
+		},
+		{
+			name:      "comma",
+			str:       "one,two,three",
+			delimiter: ",",
+			want:      []string{"one", "two", "three"},
+		},
+		{
+			name:      "whitespace and empties",
+			str:       " one , ,two,",
+			delimiter: ",",
+			want:      []string{"one", "two"},
+		},
+		{
+			name:      "multi character delimiter",
+			str:       "a::b::c:d",
+			delimiter: "::",
+			want:      []string{"a", "b", "c:d"},
+		},
+		{
+			name:      "quoted",
+			str:       `"a,b",c`,
+			delimiter: ",",
+			want:      []string{"a,b", "c"},
+		},
+		{
+			name:      "quoted keeps whitespace and empty",
+			str:       `" a ","",b`,
+			delimiter: ",",
+			want:      []string{" a ", "", "b"},
+		},
+		{
+			name:      "escaped delimiter",
+			str:       `a\,b,c`,
+			delimiter: ",",
+			want:      []string{"a,b", "c"},
+		},
+		{
+			name:      "escaped quote and backslash",
+			str:       `say \"hi\",back\\slash`,
+			delimiter: ",",
+			want:      []string{`say "hi"`, `back\slash`},
+		},
+		{
+			name:      "backslash kept before other characters",
+			str:       `C:\dir,D:\x`,
+			delimiter: ",",
+			want:      []string{`C:\dir`, `D:\x`},
+		},
+		{
+			name:      "regular expressions",
+			str:       `^\d+$,\w\s`,
+			delimiter: ",",
+			want:      []string{`^\d+$`, `\w\s`},
+		},
+		{
+			name:      "escaped multi character delimiter",
+			str:       `a\::b::c`,
+			delimiter: "::",
+			want:      []string{"a::b", "c"},
+		},
+		{
+			name:      "trailing backslash",
+			str:       `a\`,
+			delimiter: ",",
+			want:      []string{`a\`},
+		},
+		{
+			name:      "unterminated quote",
+			str:       `"a,b`,
+			delimiter: ",",
+			errMsg:    `unterminated quote in "\"a,b"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Split(tt.str, tt.delimiter)
+			if tt.errMsg != "" {
+				test.Err(t, err)
+				test.Equal(t, err.Error(), tt.errMsg)
+
+				return
+			}
+
+			test.Ok(t, err)
+			test.EqualFunc(t, got, tt.want, slices.Equal)
+		})
+	}
+}
+
+func TestJoin(t *testing.T) {
+	tests := []struct {
+		name      string   // Name of the test case
+		delimiter string   // Delimiter to join with
+		want      string   // Expected joined string
+		items     []string // Items to join
+	}{
+		{
+			name:      "empty",
+			items:     nil,
+			delimiter: ",",
+			want:      "",
+		},
+		{
+			name:      "plain",
+			items:     []string{"one", "two", "three"},
+			delimiter: ",",
+			want:      "one,two,three",
+		},
+		{
+			name:      "contains delimiter",
+			items:     []string{"a, b", "c"},
+			delimiter: ",",
+			want:      `"a, b",c`,
+		},
+		{
+			name:      "quotes and backslashes",
+			items:     []string{`say "hi"`, `C:\dir`},
+			delimiter: ",",
+			want:      `"say \"hi\"","C:\\dir"`,
+		},
+		{
+			name:      "whitespace and empty",
+			items:     []string{" a ", "", "b"},
+			delimiter: ",",
+			want:      `" a ","",b`,
+		},
+		{
+			name:      "part of a multi character delimiter",
+			items:     []string{"a:", "b"},
+			delimiter: "::",
+			want:      `"a:"::b`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Join(tt.items, tt.delimiter)
+			test.Equal(t, got, tt.want)
+
+			// Whatever goes in must come back out of Split
+			items, err := Split(got, tt.delimiter)
+			test.Ok(t, err)
+			test.EqualFunc(t, items, tt.items, slices.Equal)
+		})
+	}
+}
+
+func TestTime(t *testing.T) {
+	tests := []struct {
+		name    string // Name of the test case
+		str     string // Input string
+		want    string // Expected time, formatted as RFC3339
+		wantErr bool   // Whether we want an error
+	}{
+		{
+			name: "rfc3339",
+			str:  "2024-07-17T07:38:05Z",
+			want: "2024-07-17T07:38:05Z",
+		},
+		{
+			name: "date time",
+			str:  "2024-07-17 07:38:05",
+			want: "2024-07-17T07:38:05Z",
+		},
+		{
+			name: "date only",
+			str:  "2024-07-17",
+			want: "2024-07-17T00:00:00Z",
+		},
+		{
+			name:    "invalid",
+			str:     "not a time",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Time(tt.str)
+			test.WantErr(t, err, tt.wantErr)
+
+			if !tt.wantErr {
+				test.Equal(t, got.Format(time.RFC3339), tt.want)
+			}
+		})
+	}
+}
+
+func TestFileMode(t *testing.T) {
+	tests := []struct {
+		name    string      // Name of the test case
+		str     string      // Input string
+		want    fs.FileMode // Expected mode
+		wantErr bool        // Whether we want an error
+	}{
+		{name: "leading zero", str: "0755", want: 0o755},
+		{name: "no leading zero", str: "644", want: 0o644},
+		{name: "go prefix", str: "0o600", want: 0o600},
+		{name: "zero", str: "0", want: 0},
+		{name: "not octal", str: "0789", wantErr: true},
+		{name: "too big", str: "1777", wantErr: true},
+		{name: "empty", str: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FileMode(tt.str)
+			test.WantErr(t, err, tt.wantErr)
+			test.Equal(t, got, tt.want)
+		})
+	}
+}
+
+func TestByteSize(t *testing.T) {
+	tests := []struct {
+		name    string        // Name of the test case
+		str     string        // Input string
+		want    flag.ByteSize // Expected size
+		wantErr bool          // Whether we want an error
+	}{
+		{name: "bytes", str: "512", want: 512},
+		{name: "bytes unit", str: "512B", want: 512},
+		{name: "binary", str: "10MiB", want: 10 * flag.MiB},
+		{name: "decimal", str: "2KB", want: 2 * flag.KB},
+		{name: "case insensitive", str: "1gib", want: flag.GiB},
+		{name: "fractional", str: "1.5GB", want: 1500 * flag.MB},
+		{name: "unknown unit", str: "10XB", wantErr: true},
+		{name: "negative", str: "-1.5KB", wantErr: true},
+		{name: "part of a byte", str: "0.5B", wantErr: true},
+		{name: "overflow", str: "100000PiB", wantErr: true},
+		{name: "no number", str: "MiB", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ByteSize(tt.str)
+			test.WantErr(t, err, tt.wantErr)
+			test.Equal(t, got, tt.want)
+		})
+	}
+}
+
 func TestError(t *testing.T) {
 	got := Error(KindArgument, "test", "blah", "string", errors.New("underlying"))
 	want := `parse error: argument "test" received invalid value "blah" (expected string): underlying`
@@ -243,3 +512,36 @@ func TestErrorSlice(t *testing.T) {
 
 	test.Equal(t, got.Error(), want)
 }
+
+func TestErrorAs(t *testing.T) {
+	err := fmt.Errorf("wrapped: %w", Error(KindFlag, "count", "blah", 0, strconv.ErrSyntax))
+
+	test.True(t, errors.Is(err, Err))
+	test.True(t, errors.Is(err, strconv.ErrSyntax))
+
+	var invalid *InvalidValueError
+	test.True(t, errors.As(err, &invalid))
+	test.Equal(t, invalid.Kind, KindFlag)
+	test.Equal(t, invalid.Name, "count")
+	test.Equal(t, invalid.Value, "blah")
+	test.Equal(t, invalid.Type, "int")
+}
+
+func TestRedact(t *testing.T) {
+	err := Redact(Error(KindFlag, "api-key", "hunter2", 0, &strconv.NumError{Func: "ParseInt", Num: "hunter2", Err: strconv.ErrSyntax}))
+
+	test.Equal(t, err.Error(), `parse error: flag "api-key" received invalid value "******" (expected int)`)
+	test.False(t, strings.Contains(err.Error(), "hunter2"))
+
+	test.True(t, errors.Is(err, Err))
+	test.True(t, errors.Is(err, strconv.ErrSyntax))
+
+	var invalid *InvalidValueError
+	test.True(t, errors.As(err, &invalid))
+	test.Equal(t, invalid.Value, Mask)
+
+	// Anything else is left alone
+	other := errors.New("something else")
+	test.Equal(t, Redact(other), other)
+	test.Equal(t, Redact(nil), nil)
+}
@@ -0,0 +1,99 @@
+// Package shell implements splitting of text into words using a small, predictable
+// subset of POSIX shell quoting rules.
+//
+// It is not a shell, there is no variable expansion, globbing, pipes or
+// redirection, just enough quoting to allow arguments with spaces in them.
+package shell
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Split splits s into words.
+//
+// Words are separated by any amount of whitespace, including newlines. Within
+// a word:
+//
+//   - Text in single quotes is taken literally.
+//   - Text in double quotes is taken literally except that a backslash escapes
+//     a following '"' or '\'.
+//   - Outside of quotes, a backslash escapes the character that follows it.
+//   - A '#' at the start of a word begins a comment that runs to the end of the line.
+//
+// An empty pair of quotes produces an empty word.
+func Split(s string) ([]string, error) {
+	var (
+		words   []string
+		word    strings.Builder
+		inWord  bool // Whether we're in the middle of a word, distinguishes '' from nothing
+		escaped bool // Whether the previous character was a backslash
+		comment bool // Whether we're in a comment
+		quote   rune // The quote character we're currently inside of, or 0
+	)
+
+	for _, char := range s {
+		switch {
+		case comment:
+			comment = char != '\n'
+		case escaped:
+			if quote == '"' && char != '"' && char != '\\' {
+				// Inside double quotes the backslash is only special before these
+				word.WriteRune('\\')
+			}
+
+			word.WriteRune(char)
+
+			escaped = false
+		case quote == '\'':
+			if char == '\'' {
+				quote = 0
+			} else {
+				word.WriteRune(char)
+			}
+		case quote == '"':
+			switch char {
+			case '"':
+				quote = 0
+			case '\\':
+				escaped = true
+			default:
+				word.WriteRune(char)
+			}
+		case char == '\\':
+			inWord = true
+			escaped = true
+		case char == '\'', char == '"':
+			inWord = true
+			quote = char
+		case char == ' ', char == '\t', char == '\n', char == '\r':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+
+				inWord = false
+			}
+		case char == '#' && !inWord:
+			comment = true
+		default:
+			inWord = true
+
+			word.WriteRune(char)
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+
+	if escaped {
+		return nil, errors.New("unterminated backslash escape at end of input")
+	}
+
+	if inWord {
+		words = append(words, word.String())
+	}
+
+	return words, nil
+}
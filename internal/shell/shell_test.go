@@ -0,0 +1,96 @@
+package shell_test
+
+import (
+	"slices"
+	"testing"
+
+	"go.followtheprocess.codes/cli/internal/shell"
+	"go.followtheprocess.codes/test"
+)
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		name    string   // Name of the test case
+		input   string   // Text to split
+		errMsg  string   // If we wanted an error, what should it say
+		want    []string // Expected words
+		wantErr bool     // Whether we want an error
+	}{
+		{
+			name:  "empty",
+			input: "",
+			want:  nil,
+		},
+		{
+			name:  "simple",
+			input: "serve --port 8080",
+			want:  []string{"serve", "--port", "8080"},
+		},
+		{
+			name:  "lots of whitespace",
+			input: "  serve\t--port \n\n 8080\r\n",
+			want:  []string{"serve", "--port", "8080"},
+		},
+		{
+			name:  "single quotes",
+			input: `--name 'hello $there "you"'`,
+			want:  []string{"--name", `hello $there "you"`},
+		},
+		{
+			name:  "double quotes",
+			input: `--name "it's a \"test\" \n"`,
+			want:  []string{"--name", `it's a "test" \n`},
+		},
+		{
+			name:  "backslash",
+			input: `hello\ world \'quoted\'`,
+			want:  []string{"hello world", "'quoted'"},
+		},
+		{
+			name:  "adjacent quotes join",
+			input: `--name=a' 'b"c"`,
+			want:  []string{"--name=a bc"},
+		},
+		{
+			name:  "empty quotes",
+			input: `one '' ""`,
+			want:  []string{"one", "", ""},
+		},
+		{
+			name:  "comments",
+			input: "# A comment\n--force # trailing\nvalue#not-a-comment",
+			want:  []string{"--force", "value#not-a-comment"},
+		},
+		{
+			name:    "unterminated single",
+			input:   "'oops",
+			wantErr: true,
+			errMsg:  "unterminated ' quote",
+		},
+		{
+			name:    "unterminated double",
+			input:   `"oops`,
+			wantErr: true,
+			errMsg:  `unterminated " quote`,
+		},
+		{
+			name:    "trailing backslash",
+			input:   `oops\`,
+			wantErr: true,
+			errMsg:  "unterminated backslash escape at end of input",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := shell.Split(tt.input)
+			test.WantErr(t, err, tt.wantErr)
+
+			if err != nil {
+				test.Equal(t, err.Error(), tt.errMsg)
+			}
+
+			test.EqualFunc(t, got, tt.want, slices.Equal)
+		})
+	}
+}
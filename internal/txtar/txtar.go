@@ -0,0 +1,135 @@
+// Package txtar implements parsing of the trivial text-based file archive format
+// used by the Go toolchain for test scripts.
+//
+// An archive is a comment followed by a sequence of files, each file is introduced
+// by a marker line of the form "-- name --" and its contents run until the next
+// marker or the end of the archive:
+//
+//	Comment, in our case the script to run.
+//	-- hello.txt --
+//	Hello, world!
+//	-- dir/other.txt --
+//	Some more text
+//
+// It's a deliberately tiny reimplementation of golang.org/x/tools/txtar so
+// as not to pull the whole of x/tools into the dependency graph.
+package txtar
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Archive is a parsed txtar archive.
+type Archive struct {
+	Comment string // The text before the first file marker
+	Files   []File // The files in the archive, in order
+}
+
+// File is a single file in an archive.
+type File struct {
+	Name string // The file name, slash separated and relative to the archive
+	Data string // The file contents
+}
+
+// Parse parses the archive in data.
+//
+// Parse never fails, a malformed marker line is simply treated as part of the
+// preceding comment or file.
+func Parse(data []byte) *Archive {
+	archive := &Archive{}
+
+	var (
+		current *File           // The file we're currently reading, nil if in the comment
+		buf     strings.Builder // Contents of the comment or current file
+	)
+
+	flush := func() {
+		if current == nil {
+			archive.Comment = buf.String()
+		} else {
+			current.Data = buf.String()
+			archive.Files = append(archive.Files, *current)
+		}
+
+		buf.Reset()
+	}
+
+	for line := range bytes.Lines(data) {
+		if name, ok := marker(line); ok {
+			flush()
+
+			current = &File{Name: name}
+
+			continue
+		}
+
+		buf.Write(line)
+
+		if !bytes.HasSuffix(line, []byte("\n")) {
+			// Files always end in a newline, even if the last line in the archive didn't
+			buf.WriteByte('\n')
+		}
+	}
+
+	flush()
+
+	return archive
+}
+
+// ParseFile reads and parses the archive at path.
+func ParseFile(path string) (*Archive, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read archive: %w", err)
+	}
+
+	return Parse(data), nil
+}
+
+// Extract writes every file in the archive beneath dir, creating any intermediate
+// directories as needed.
+func (a *Archive) Extract(dir string) error {
+	for _, file := range a.Files {
+		if !filepath.IsLocal(file.Name) {
+			return fmt.Errorf("file name %q is not local to the archive", file.Name)
+		}
+
+		path := filepath.Join(dir, filepath.FromSlash(file.Name))
+
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil { //nolint:mnd // Standard dir perms
+			return fmt.Errorf("could not create directory for %s: %w", file.Name, err)
+		}
+
+		if err := os.WriteFile(path, []byte(file.Data), 0o644); err != nil { //nolint:mnd,gosec // Standard file perms
+			return fmt.Errorf("could not write %s: %w", file.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// marker reports whether line is a file marker "-- name --", returning the name if so.
+func marker(line []byte) (name string, ok bool) {
+	line = bytes.TrimRight(line, "\r\n")
+
+	rest, ok := bytes.CutPrefix(line, []byte("-- "))
+	if !ok {
+		return "", false
+	}
+
+	rest, ok = bytes.CutSuffix(rest, []byte(" --"))
+	if !ok {
+		return "", false
+	}
+
+	name = strings.TrimSpace(string(rest))
+	if name == "" {
+		return "", false
+	}
+
+	return name, true
+}
@@ -0,0 +1,59 @@
+package txtar_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.followtheprocess.codes/cli/internal/txtar"
+	"go.followtheprocess.codes/test"
+)
+
+func TestParse(t *testing.T) {
+	data := `exec mytool
+stdout hello
+-- hello.txt --
+Hello, world!
+-- dir/other.txt --
+-- not a marker
+-- last.txt --
+no trailing newline`
+
+	archive := txtar.Parse([]byte(data))
+
+	test.Equal(t, archive.Comment, "exec mytool\nstdout hello\n")
+	test.Equal(t, len(archive.Files), 3)
+
+	test.Equal(t, archive.Files[0].Name, "hello.txt")
+	test.Equal(t, archive.Files[0].Data, "Hello, world!\n")
+
+	test.Equal(t, archive.Files[1].Name, "dir/other.txt")
+	test.Equal(t, archive.Files[1].Data, "-- not a marker\n")
+
+	test.Equal(t, archive.Files[2].Name, "last.txt")
+	test.Equal(t, archive.Files[2].Data, "no trailing newline\n")
+}
+
+func TestParseNoFiles(t *testing.T) {
+	archive := txtar.Parse([]byte("just a comment\n"))
+	test.Equal(t, archive.Comment, "just a comment\n")
+	test.Equal(t, len(archive.Files), 0)
+}
+
+func TestExtract(t *testing.T) {
+	dir := t.TempDir()
+
+	archive := txtar.Parse([]byte("-- a.txt --\nA\n-- nested/b.txt --\nB\n"))
+	test.Ok(t, archive.Extract(dir))
+
+	a, err := os.ReadFile(filepath.Join(dir, "a.txt"))
+	test.Ok(t, err)
+	test.Equal(t, string(a), "A\n")
+
+	b, err := os.ReadFile(filepath.Join(dir, "nested", "b.txt"))
+	test.Ok(t, err)
+	test.Equal(t, string(b), "B\n")
+
+	escaping := txtar.Parse([]byte("-- ../escape.txt --\nnope\n"))
+	test.Err(t, escaping.Extract(dir))
+}
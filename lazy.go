@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// Lazy returns a [Builder] for a subcommand that is only built when it's needed, for
+// use with [SubCommands].
+//
+// Normally every subcommand in the tree is built by [New], so a tool with lots of
+// subcommands pays the cost of setting up all of their flags and arguments on every
+// invocation, even though only one of them will run. A lazy subcommand registers only its
+// name, short description and help group (see [Group], empty for none) up front, which
+// is all its parent's help needs. Builder is called the first time the command is
+// actually needed: when it (or one of its own subcommands) is run.
+//
+// The command returned by builder must have the same name as the one given to Lazy,
+// and the same group if it sets one. Any error it returns is returned from
+// [Command.Execute] when the command is needed.
+//
+//	cli.New(
+//		"mytool",
+//		cli.SubCommands(
+//			cli.Lazy("serve", "Run the server", "", buildServe),
+//			cli.Lazy("migrate", "Run database migrations", "Database", buildMigrate),
+//		),
+//	)
+func Lazy(name, short, group string, builder Builder) Builder {
+	return func() (*Command, error) {
+		if name == "" {
+			return nil, errors.New("lazy subcommand name cannot be empty")
+		}
+
+		if builder == nil {
+			return nil, fmt.Errorf("lazy subcommand %q has a nil builder", name)
+		}
+
+		return &Command{name: name, short: short, group: group, lazy: builder}, nil
+	}
+}
+
+// resolve returns the real command for cmd. If cmd is the placeholder for a lazy
+// command it is built and swapped into its parent's subcommands in place of the
+// placeholder, so from then on the built command (which everything, like its flags, is
+// bound to) is the one found in the tree. Commands that have already been built are
+// returned as they are.
+//
+// If the builder fails, the placeholder stays in the tree and the error is returned.
+func (cmd *Command) resolve() (*Command, error) {
+	if cmd.lazy == nil {
+		return cmd, nil
+	}
+
+	built, err := cmd.lazy()
+	if err != nil {
+		return nil, fmt.Errorf("could not build subcommand %q: %w", cmd.name, err)
+	}
+
+	if built == nil {
+		return nil, fmt.Errorf("could not build subcommand %q: builder returned a nil command", cmd.name)
+	}
+
+	if built.name != cmd.name {
+		return nil, fmt.Errorf("lazy subcommand %q built a command named %q", cmd.name, built.name)
+	}
+
+	if built.group != "" && built.group != cmd.group {
+		return nil, fmt.Errorf("lazy subcommand %q in group %q built a command in group %q", cmd.name, cmd.group, built.group)
+	}
+
+	if built.group == "" {
+		built.group = cmd.group
+	}
+
+	built.parent = cmd.parent
+
+	if cmd.parent != nil {
+		if i := slices.Index(cmd.parent.subcommands, cmd); i != -1 {
+			cmd.parent.subcommands[i] = built
+		}
+	}
+
+	return built, nil
+}
@@ -7,6 +7,7 @@ import (
 	"io"
 	"slices"
 	"strings"
+	"unicode"
 
 	"go.followtheprocess.codes/cli/arg"
 	"go.followtheprocess.codes/cli/flag"
@@ -118,6 +119,159 @@ func NoColour(noColour bool) Option {
 	return noColourOpt{noColour: noColour}
 }
 
+type pagerOpt struct{ enabled bool }
+
+func (o pagerOpt) apply(cmd *Command) error {
+	cmd.pager = o.enabled
+
+	return nil
+}
+
+// Pager is an [Option] that shows help text too long to fit in the terminal through
+// the user's pager, in the same way git does.
+//
+// Paging only happens when stderr is a terminal. The pager is taken from
+// $<NAME>_PAGER (e.g. $MYTOOL_PAGER for a command called "mytool"), then $PAGER,
+// defaulting to "less -R". Setting $NO_PAGER, or setting the pager to an empty string
+// or "cat", disables paging. If the pager cannot be started, output is written directly.
+//
+// The same behaviour is available to run functions through [Command.Pager].
+//
+// Like [Stdout], only the setting on the root command has any effect.
+//
+//	cli.New("mytool", cli.Pager(true))
+func Pager(enabled bool) Option {
+	return pagerOpt{enabled: enabled}
+}
+
+type interactiveOpt struct{ interactive bool }
+
+func (o interactiveOpt) apply(cmd *Command) error {
+	cmd.interactive = o.interactive
+
+	return nil
+}
+
+// Interactive is an [Option] that treats stdin as interactive, so flags and arguments
+// with [Prompt] or [ArgPrompt] ask for their values, even when stdin is not a terminal.
+//
+// Without it, prompts only appear when stdin is a terminal. This is for feeding scripted
+// answers to prompts through [Stdin], e.g. in tests.
+//
+// Like [Stdout], only the setting on the root command has any effect.
+//
+//	cli.New("test", cli.Stdin(strings.NewReader("yes\n")), cli.Interactive(true))
+func Interactive(interactive bool) Option {
+	return interactiveOpt{interactive: interactive}
+}
+
+type responseFilesOpt struct{}
+
+func (o responseFilesOpt) apply(cmd *Command) error {
+	cmd.responseFiles = true
+
+	return nil
+}
+
+// ResponseFiles is an [Option] that enables response files, any argument of the
+// form "@path" is replaced by the arguments contained in the file at path before
+// anything else is parsed. This is useful for argument lists too long for the
+// command line or just too unwieldy to type.
+//
+// Each line of a response file may hold any number of arguments, split using
+// shell-like quoting rules, so arguments containing spaces must be quoted. A '#'
+// at the start of an argument begins a comment that runs to the end of the line:
+//
+//	# args.txt
+//	serve --port 8080
+//	--name "my server" # Quoted as it has a space
+//	@more-args.txt
+//
+// Response files may reference other response files, up to a depth of 10. Relative
+// paths are resolved against the current working directory. Arguments after a "--"
+// terminator, whether on the command line or in a response file, are never expanded.
+//
+// Like [Stdout], only the setting on the root command has any effect.
+//
+//	cli.New("mytool", cli.ResponseFiles())
+func ResponseFiles() Option {
+	return responseFilesOpt{}
+}
+
+type pluginsOpt struct{ prefix string }
+
+func (o pluginsOpt) apply(cmd *Command) error {
+	if o.prefix == "" {
+		return errors.New("cannot set plugin prefix to an empty string")
+	}
+
+	if strings.ContainsAny(o.prefix, " /\\") {
+		return fmt.Errorf("invalid plugin prefix %q, must not contain spaces or path separators", o.prefix)
+	}
+
+	cmd.pluginPrefix = o.prefix
+
+	return nil
+}
+
+// Plugins is an [Option] that lets a [Command] be extended by external executables,
+// in the style of git. When the user asks for a subcommand that doesn't exist, an
+// executable named "<prefix>-<name>" is looked for on $PATH and run in its place,
+// with any arguments after the name passed to it:
+//
+//	# Runs 'mytool-deploy --env prod' if mytool has no deploy subcommand
+//	$ mytool deploy --env prod
+//
+// The plugin shares the command's stdin, stdout and stderr and is killed if the context
+// passed to [Command.Execute] is cancelled. If it exits with a non-zero status, the error
+// returned from Execute wraps an [*exec.ExitError] so the status can be passed on. Flags
+// before the plugin's name still belong to the command.
+//
+// Plugins found on $PATH are listed in the command's help under their own section, and a
+// "plugins" subcommand is added so users can see where each one was found with
+// 'mytool plugins list'.
+//
+// Note that if the command takes positional arguments, a plugin takes priority over an
+// argument with the same name.
+//
+//	cli.New("mytool", cli.Plugins("mytool"))
+func Plugins(prefix string) Option {
+	return pluginsOpt{prefix: prefix}
+}
+
+type shellOpt struct{}
+
+func (o shellOpt) apply(cmd *Command) error {
+	cmd.shell = true
+
+	return nil
+}
+
+// Shell is an [Option] that adds a "shell" subcommand to a [Command], which starts an
+// interactive prompt where the user can type any of the command's subcommands over and
+// over without re-launching the program, so things like authentication and connections
+// set up in the program stay warm between them.
+//
+//	$ mytool shell
+//	mytool> users list --limit 10
+//	...
+//	mytool> users delete bob
+//	...
+//	mytool> exit
+//
+// Each line is split into arguments using shell-like quoting rules and parsed exactly as
+// if it had been typed after the command on the command line. Flags are reset to their
+// defaults before each line. Errors are printed and the shell carries on.
+//
+// When stdin is a terminal the shell supports line editing, history with the up and down
+// arrows and tab completion of subcommands and flags. The 'history' built-in lists the
+// lines entered so far, and 'exit' (or Ctrl-D) leaves the shell.
+//
+//	cli.New("mytool", cli.Shell(), cli.SubCommands(buildUsers))
+func Shell() Option {
+	return shellOpt{}
+}
+
 type shortOpt struct{ short string }
 
 func (o shortOpt) apply(cmd *Command) error {
@@ -210,6 +364,64 @@ func Example(comment, command string) Option {
 	return exampleOpt{comment: comment, command: command}
 }
 
+type helpTopicOpt struct {
+	name  string
+	short string
+	body  string
+}
+
+func (o helpTopicOpt) apply(cmd *Command) error {
+	if o.name == "" {
+		return errors.New("help topic name cannot be empty")
+	}
+
+	if strings.ContainsFunc(o.name, unicode.IsSpace) {
+		return fmt.Errorf("help topic name %q cannot contain whitespace", o.name)
+	}
+
+	if o.short == "" {
+		return fmt.Errorf("help topic %q short description cannot be empty", o.name)
+	}
+
+	if o.body == "" {
+		return fmt.Errorf("help topic %q body cannot be empty", o.name)
+	}
+
+	if _, exists := findTopic(cmd, o.name); exists {
+		return fmt.Errorf("help topic %q already defined", o.name)
+	}
+
+	cmd.topics = append(cmd.topics, helpTopic{
+		name:  o.name,
+		short: strings.TrimSpace(o.short),
+		body:  strings.TrimSpace(o.body),
+	})
+
+	return nil
+}
+
+// HelpTopic is an [Option] that adds a help topic to a [Command].
+//
+// Help topics are pages of documentation for concepts rather than runnable commands,
+// e.g. the environment variables a tool reads or the format of its config file. They
+// are listed under "Additional Help Topics" in the help text, along with their one
+// line short description.
+//
+// Help topics are shown with the built-in "help" subcommand, which every command with
+// subcommands or help topics gets automatically (unless one has already been defined).
+// It shows the full body of a topic, or the help text for any command in the tree:
+//
+//	mytool help environment
+//	mytool help serve start
+//
+// For consistency of formatting, all leading and trailing whitespace is stripped from
+// short and body. Calls to [HelpTopic] are additive, topic names must be unique.
+//
+//	cli.New("mytool", cli.HelpTopic("environment", "Environment variables used by mytool", envDocs))
+func HelpTopic(name, short, body string) Option {
+	return helpTopicOpt{name: name, short: short, body: body}
+}
+
 type runOpt struct {
 	run func(ctx context.Context, cmd *Command) error
 }
@@ -349,12 +561,44 @@ func (o subCommandsOpt) apply(cmd *Command) error {
 //
 // Sub commands must have unique names, any duplicates will result in an error.
 //
+// A command with subcommands automatically gets a "help" subcommand, so users can type
+// e.g. 'mytool help serve start' as an alternative to 'mytool serve start --help'. Defining
+// a subcommand called "help" yourself replaces the built-in one.
+//
 // This option is additive and can be called as many times as desired, subcommands are
 // effectively appended on every call.
 func SubCommands(builders ...Builder) Option {
 	return subCommandsOpt{builders: builders}
 }
 
+type groupOpt struct{ title string }
+
+func (o groupOpt) apply(cmd *Command) error {
+	if o.title == "" {
+		return errors.New("cannot set command group to an empty string")
+	}
+
+	cmd.group = strings.TrimSpace(o.title)
+
+	return nil
+}
+
+// Group is an [Option] that places a [Command] under a titled section when it
+// is listed in its parent's help text.
+//
+// Large CLIs with many subcommands can use groups to organise them into related
+// sections. Sections are shown in the order in which their groups are first declared
+// among the subcommands, any subcommands without a group are listed under the
+// default "Commands" section. When every subcommand is in a group that section is
+// left out, along with the subcommands added automatically such as help.
+//
+// Successive calls will simply overwrite any previous calls.
+//
+//	cli.New("network", cli.Group("Management Commands"))
+func Group(title string) Option {
+	return groupOpt{title: title}
+}
+
 type flagOpt[T flag.Flaggable] struct {
 	target  *T
 	name    string
@@ -364,7 +608,11 @@ type flagOpt[T flag.Flaggable] struct {
 }
 
 func (o flagOpt[T]) apply(cmd *Command) error {
-	if _, ok := cmd.flags.Get(o.name); ok {
+	if existing, ok := cmd.flags.Get(o.name); ok {
+		if existing.Name() != o.name {
+			return fmt.Errorf("flag %q already defined as an alias of flag %q", o.name, existing.Name())
+		}
+
 		return fmt.Errorf("flag %q already defined", o.name)
 	}
 
@@ -476,6 +724,98 @@ func ArgDefault[T arg.Argable](value T) ArgOption[T] {
 	return argDefaultOpt[T]{value: value}
 }
 
+type argFromFileOpt[T arg.Argable] struct{}
+
+//nolint:unused // Satisfies the unexported ArgOption.apply method, staticcheck can't see across the interface.
+func (o argFromFileOpt[T]) apply(cfg *internalarg.Config[T]) error {
+	cfg.FromFile = true
+
+	return nil
+}
+
+// ArgFromFile is a [cli.ArgOption] that allows the argument's value to be read from
+// a file ("@path") or stdin ("-") rather than given directly on the command line.
+//
+// It behaves exactly like [FromFile] does for flags, except that as positional
+// arguments cannot be joined to anything, it cannot be combined with [ResponseFiles]
+// to read from a file, only from stdin.
+//
+//	// mytool apply @manifest.yaml
+//	var manifest string
+//	cli.Arg(&manifest, "manifest", "The manifest to apply", cli.ArgFromFile[string]())
+func ArgFromFile[T arg.Argable]() ArgOption[T] {
+	return argFromFileOpt[T]{}
+}
+
+type argPromptOpt[T arg.Argable] struct{}
+
+//nolint:unused // Satisfies the unexported ArgOption.apply method, staticcheck can't see across the interface.
+func (o argPromptOpt[T]) apply(cfg *internalarg.Config[T]) error {
+	cfg.Prompt = true
+
+	return nil
+}
+
+// ArgPrompt is a [cli.ArgOption] that interactively asks the user for the argument's
+// value when it is not given on the command line, see [Prompt] for how prompts behave.
+//
+// If the argument is required (i.e. has no [ArgDefault]) the user is asked until
+// they give an answer, otherwise no answer means the default.
+//
+//	var name string
+//	cli.Arg(&name, "name", "Name of the new project", cli.ArgPrompt[string]())
+func ArgPrompt[T arg.Argable]() ArgOption[T] {
+	return argPromptOpt[T]{}
+}
+
+type argChoicesOpt[T arg.Argable] struct{ choices []T }
+
+//nolint:unused // Satisfies the unexported ArgOption.apply method, staticcheck can't see across the interface.
+func (o argChoicesOpt[T]) apply(cfg *internalarg.Config[T]) error {
+	if len(o.choices) == 0 {
+		return errors.New("argument choices cannot be empty")
+	}
+
+	cfg.Choices = o.choices
+
+	return nil
+}
+
+// ArgChoices is a [cli.ArgOption] that restricts the values an argument may take,
+// it is the argument equivalent of [Choices].
+//
+//	var shell string
+//	cli.Arg(&shell, "shell", "Shell to generate completions for", cli.ArgChoices("bash", "zsh", "fish"))
+func ArgChoices[T arg.Argable](choices ...T) ArgOption[T] {
+	return argChoicesOpt[T]{choices: choices}
+}
+
+type argValidateOpt[T arg.Argable] struct{ validators []func(T) error }
+
+//nolint:unused // Satisfies the unexported ArgOption.apply method, staticcheck can't see across the interface.
+func (o argValidateOpt[T]) apply(cfg *internalarg.Config[T]) error {
+	if len(o.validators) == 0 {
+		return errors.New("argument validators cannot be empty")
+	}
+
+	if slices.ContainsFunc(o.validators, func(validator func(T) error) bool { return validator == nil }) {
+		return errors.New("argument validators cannot be nil")
+	}
+
+	cfg.Validators = append(cfg.Validators, o.validators...)
+
+	return nil
+}
+
+// ArgValidate is a [cli.ArgOption] that checks the argument's value after it's parsed,
+// it is the argument equivalent of [Validate].
+//
+//	var dir string
+//	cli.Arg(&dir, "dir", "Directory to serve", cli.ArgValidate(cli.DirExists))
+func ArgValidate[T arg.Argable](validators ...func(T) error) ArgOption[T] {
+	return argValidateOpt[T]{validators: validators}
+}
+
 type envOpt[T flag.Flaggable] struct{ name string }
 
 //nolint:unused // Satisfies the unexported FlagOption.apply method, staticcheck can't see across the interface.
@@ -528,6 +868,378 @@ func FlagDefault[T flag.Flaggable](value T) FlagOption[T] {
 	return flagDefaultOpt[T]{value: value}
 }
 
+type noArgDefaultOpt[T flag.Flaggable] struct{ value T }
+
+//nolint:unused // Satisfies the unexported FlagOption.apply method, staticcheck can't see across the interface.
+func (o noArgDefaultOpt[T]) apply(cfg *internalflag.Config[T]) error {
+	cfg.NoArgDefault = &o.value
+
+	return nil
+}
+
+// NoArgDefault is a [FlagOption] that lets a flag be given without a value, in which
+// case it takes the value passed here. Explicit values must then be given with an
+// "=" e.g. --colour=never, as "--colour never" would be the flag on its own followed
+// by the positional argument "never".
+//
+// This is how bool and [flag.Count] flags always behave, NoArgDefault makes any
+// other (non-slice) type work the same way. The implied value is shown in help
+// text e.g. string[=always].
+//
+//	var colour string
+//	cli.Flag(&colour, "colour", 'c', "Colour the output", cli.FlagDefault[string]("auto"), cli.NoArgDefault[string]("always"))
+func NoArgDefault[T flag.Flaggable](value T) FlagOption[T] {
+	return noArgDefaultOpt[T]{value: value}
+}
+
+type delimiterOpt[T flag.Flaggable] struct{ delimiter string }
+
+//nolint:unused // Satisfies the unexported FlagOption.apply method, staticcheck can't see across the interface.
+func (o delimiterOpt[T]) apply(cfg *internalflag.Config[T]) error {
+	if o.delimiter == "" {
+		return errors.New("delimiter cannot be empty")
+	}
+
+	if strings.ContainsAny(o.delimiter, `"\`) {
+		return fmt.Errorf("delimiter %q cannot contain quotes or backslashes", o.delimiter)
+	}
+
+	cfg.Delimiter = o.delimiter
+
+	return nil
+}
+
+// Delimiter is a [FlagOption] for slice flags that splits each value given to the flag
+// into items on delimiter, so "--items one,two,three" has the same effect as giving
+// --items three times. Values given as separate flags are still added together.
+//
+// An item containing the delimiter may be wrapped in double quotes or have the delimiter
+// escaped with a backslash e.g. --items '"a,b",c' or --items 'a\,b,c'. Any other
+// backslash is kept as it is, so paths like 'C:\dir' need no escaping. Surrounding
+// whitespace is trimmed from unquoted items.
+//
+// The flag's environment variable, if it has one, is split in the same way.
+//
+//	var items []string
+//	cli.Flag(&items, "items", 'i', "Items to process", cli.Delimiter[[]string](","))
+func Delimiter[T flag.Flaggable](delimiter string) FlagOption[T] {
+	return delimiterOpt[T]{delimiter: delimiter}
+}
+
+type replaceDefaultOpt[T flag.Flaggable] struct{}
+
+//nolint:unused // Satisfies the unexported FlagOption.apply method, staticcheck can't see across the interface.
+func (o replaceDefaultOpt[T]) apply(cfg *internalflag.Config[T]) error {
+	cfg.ReplaceDefault = true
+
+	return nil
+}
+
+// ReplaceDefault is a [FlagOption] for slice flags that makes the values given to the
+// flag replace its default, rather than being added to it.
+//
+// Without it, a flag with a default of []string{"a"} given "--items b" holds [a b], with it
+// the flag holds just [b]. The same goes for the flag's environment variable, which
+// replaces the default, and for the command line, which replaces both.
+//
+//	var items []string
+//	cli.Flag(&items, "items", 'i', "Items to process", cli.FlagDefault([]string{"a"}), cli.ReplaceDefault[[]string]())
+func ReplaceDefault[T flag.Flaggable]() FlagOption[T] {
+	return replaceDefaultOpt[T]{}
+}
+
+type flagGroupOpt[T flag.Flaggable] struct{ title string }
+
+//nolint:unused // Satisfies the unexported FlagOption.apply method, staticcheck can't see across the interface.
+func (o flagGroupOpt[T]) apply(cfg *internalflag.Config[T]) error {
+	if o.title == "" {
+		return errors.New("flag group cannot be empty")
+	}
+
+	cfg.Group = strings.TrimSpace(o.title)
+
+	return nil
+}
+
+// FlagGroup is a [FlagOption] that lists a flag under a titled section in the
+// help text, rather than the default "Options" section.
+//
+// Groups are shown after the default section, in the order in which they are first
+// declared on the command. Within a group, flags are listed alphabetically.
+//
+//	var port int
+//	cli.Flag(&port, "port", 'p', "Port to listen on", cli.FlagGroup[int]("Networking"))
+func FlagGroup[T flag.Flaggable](title string) FlagOption[T] {
+	return flagGroupOpt[T]{title: title}
+}
+
+type fromFileOpt[T flag.Flaggable] struct{}
+
+//nolint:unused // Satisfies the unexported FlagOption.apply method, staticcheck can't see across the interface.
+func (o fromFileOpt[T]) apply(cfg *internalflag.Config[T]) error {
+	cfg.FromFile = true
+
+	return nil
+}
+
+// FromFile is a [FlagOption] that allows the flag's value to be read from a file or
+// stdin rather than given directly on the command line, ideal for secrets and large
+// payloads.
+//
+// A value of "@path" reads the value from the file at path and a value of "-" reads
+// it from the command's stdin (see [Command.Stdin]), any other value is used as is.
+// Trailing newlines are trimmed from the contents, and for slice flags each non-empty
+// line is a separate value.
+//
+// Values from environment variables (see [Env]) are always taken literally.
+//
+// If the command also uses [ResponseFiles], "@path" would be expanded as a response
+// file before the flag ever sees it, so the value must be joined to the flag with
+// an '=' e.g. --token=@/run/secrets/token.
+//
+//	// mytool deploy --token @/run/secrets/token --body -
+//	var token, body string
+//	cli.Flag(&token, "token", flag.NoShortHand, "API token", cli.FromFile[string]())
+//	cli.Flag(&body, "body", 'b', "Request body", cli.FromFile[string]())
+func FromFile[T flag.Flaggable]() FlagOption[T] {
+	return fromFileOpt[T]{}
+}
+
+type sensitiveOpt[T flag.Flaggable] struct{}
+
+//nolint:unused // Satisfies the unexported FlagOption.apply method, staticcheck can't see across the interface.
+func (o sensitiveOpt[T]) apply(cfg *internalflag.Config[T]) error {
+	cfg.Sensitive = true
+
+	return nil
+}
+
+// Sensitive is a [FlagOption] that marks a flag as holding a secret e.g. an API key,
+// its value is masked everywhere the library would otherwise show it: the default in
+// the help text, the flag's string representation and any error messages about an
+// invalid value.
+//
+// The target variable still receives the real value.
+//
+// Sensitive pairs well with [FromFile] and [Env] so that secrets need never be
+// typed on the command line at all.
+//
+//	var apiKey string
+//	cli.Flag(&apiKey, "api-key", flag.NoShortHand, "API key", cli.Sensitive[string](), cli.Env[string]("MYTOOL_API_KEY"))
+func Sensitive[T flag.Flaggable]() FlagOption[T] {
+	return sensitiveOpt[T]{}
+}
+
+type promptOpt[T flag.Flaggable] struct{}
+
+//nolint:unused // Satisfies the unexported FlagOption.apply method, staticcheck can't see across the interface.
+func (o promptOpt[T]) apply(cfg *internalflag.Config[T]) error {
+	cfg.Prompt = true
+
+	return nil
+}
+
+// Prompt is a [FlagOption] that interactively asks the user for the flag's value
+// when it is not given on the command line or by its environment variable (see [Env]),
+// using the flag's usage as the question.
+//
+// The kind of prompt depends on the flag:
+//
+//   - bool flags ask for a yes/no confirmation.
+//   - Flags with [Choices] ask the user to pick one.
+//   - [Sensitive] flags ask for a password, which is not echoed back.
+//   - Anything else asks for text.
+//
+// An invalid answer is reported and the question asked again, and giving no answer
+// leaves the flag at its default.
+//
+// Prompts are only shown when the command's stdin is a terminal (or it's marked
+// with [Interactive], so prompts can be tested), and commands with prompts get a
+// --no-input flag to turn them off for scripts.
+//
+//	var env string
+//	cli.Flag(&env, "env", 'e', "Environment to deploy to", cli.Prompt[string](), cli.Choices("dev", "prod"))
+func Prompt[T flag.Flaggable]() FlagOption[T] {
+	return promptOpt[T]{}
+}
+
+type choicesOpt[T flag.Flaggable] struct{ choices []T }
+
+//nolint:unused // Satisfies the unexported FlagOption.apply method, staticcheck can't see across the interface.
+func (o choicesOpt[T]) apply(cfg *internalflag.Config[T]) error {
+	if len(o.choices) == 0 {
+		return errors.New("flag choices cannot be empty")
+	}
+
+	cfg.Choices = o.choices
+
+	return nil
+}
+
+// Choices is a [FlagOption] that restricts the values a flag may take, giving it
+// any other value is an error. Flags with choices and [Prompt] ask the user to pick
+// from the list.
+//
+// Choices may not be used with slice flags.
+//
+//	var format string
+//	cli.Flag(&format, "format", 'f', "Output format", cli.Choices("json", "yaml", "text"))
+func Choices[T flag.Flaggable](choices ...T) FlagOption[T] {
+	return choicesOpt[T]{choices: choices}
+}
+
+type requiredOpt[T flag.Flaggable] struct{}
+
+//nolint:unused // Satisfies the unexported FlagOption.apply method, staticcheck can't see across the interface.
+func (o requiredOpt[T]) apply(cfg *internalflag.Config[T]) error {
+	cfg.Required = true
+
+	return nil
+}
+
+// Required is a [FlagOption] that makes a flag mandatory, executing the command without
+// giving the flag a value (on the command line, by its environment variable or in
+// answer to a [Prompt]) returns a [MissingFlagError].
+//
+//	var token string
+//	cli.Flag(&token, "token", 't', "API token", cli.Required[string]())
+func Required[T flag.Flaggable]() FlagOption[T] {
+	return requiredOpt[T]{}
+}
+
+type validateOpt[T flag.Flaggable] struct{ validators []func(T) error }
+
+//nolint:unused // Satisfies the unexported FlagOption.apply method, staticcheck can't see across the interface.
+func (o validateOpt[T]) apply(cfg *internalflag.Config[T]) error {
+	if len(o.validators) == 0 {
+		return errors.New("flag validators cannot be empty")
+	}
+
+	if slices.ContainsFunc(o.validators, func(validator func(T) error) bool { return validator == nil }) {
+		return errors.New("flag validators cannot be nil")
+	}
+
+	cfg.Validators = append(cfg.Validators, o.validators...)
+
+	return nil
+}
+
+// Validate is a [FlagOption] that checks every value the flag is given, on the command line,
+// by its environment variable or in answer to a [Prompt], after it's parsed. If any of
+// the validators returns an error the value is rejected with a parse error (matching
+// [ErrParse]) explaining why.
+//
+// Validators run in order, and may be any func(T) error or one of the built ins e.g.
+// [Between], [Min], [Max], [Matches], [NonEmpty], [FileExists] or [DirExists]. For slice
+// flags, the validators are passed the whole slice each time an element is added.
+//
+//	var port int
+//	cli.Flag(&port, "port", 'p', "Port to listen on", cli.Validate(cli.Between(1, 65535)))
+func Validate[T flag.Flaggable](validators ...func(T) error) FlagOption[T] {
+	return validateOpt[T]{validators: validators}
+}
+
+type deprecatedFlagOpt[T flag.Flaggable] struct{ message string }
+
+//nolint:unused // Satisfies the unexported FlagOption.apply method, staticcheck can't see across the interface.
+func (o deprecatedFlagOpt[T]) apply(cfg *internalflag.Config[T]) error {
+	if o.message == "" {
+		return errors.New("deprecation message cannot be empty")
+	}
+
+	cfg.Deprecated = o.message
+
+	return nil
+}
+
+// DeprecatedFlag is a [FlagOption] that marks a flag as deprecated. The flag still works
+// but every time it's used, on the command line or by its environment variable, a
+// warning including message is written to the command's [Command.Stderr].
+//
+// Combine with [ReplacedBy] to have the flag's value forwarded to its replacement, and
+// [HiddenFlag] to stop advertising it in help text.
+//
+//	var addr string
+//	cli.Flag(&addr, "addr", flag.NoShortHand, "Address to listen on", cli.DeprecatedFlag[string]("use --listen instead"))
+func DeprecatedFlag[T flag.Flaggable](message string) FlagOption[T] {
+	return deprecatedFlagOpt[T]{message: message}
+}
+
+type replacedByOpt[T flag.Flaggable] struct{ name string }
+
+//nolint:unused // Satisfies the unexported FlagOption.apply method, staticcheck can't see across the interface.
+func (o replacedByOpt[T]) apply(cfg *internalflag.Config[T]) error {
+	if o.name == "" {
+		return errors.New("replacement flag name cannot be empty")
+	}
+
+	cfg.ReplacedBy = o.name
+
+	if cfg.Deprecated == "" {
+		cfg.Deprecated = "use --" + o.name + " instead"
+	}
+
+	return nil
+}
+
+// ReplacedBy is a [FlagOption] for flags being renamed, any values given to the flag are
+// also given to the flag called name (which must exist on the same command), unless
+// that flag was set itself. This way the run function only needs to read the new flag.
+//
+// The flag is marked as deprecated, with the message "use --<name> instead" unless a
+// [DeprecatedFlag] option sets another.
+//
+//	var addr, listen string
+//	cli.Flag(&listen, "listen", 'l', "Address to listen on"),
+//	cli.Flag(&addr, "addr", flag.NoShortHand, "Address to listen on", cli.ReplacedBy[string]("listen"), cli.HiddenFlag[string]())
+func ReplacedBy[T flag.Flaggable](name string) FlagOption[T] {
+	return replacedByOpt[T]{name: name}
+}
+
+type hiddenFlagOpt[T flag.Flaggable] struct{}
+
+//nolint:unused // Satisfies the unexported FlagOption.apply method, staticcheck can't see across the interface.
+func (o hiddenFlagOpt[T]) apply(cfg *internalflag.Config[T]) error {
+	cfg.Hidden = true
+
+	return nil
+}
+
+// HiddenFlag is a [FlagOption] that leaves a flag out of help text and shell completion,
+// while it still parses as normal. Useful for internal or debugging flags, or deprecated
+// ones that shouldn't be advertised any more.
+//
+//	var trace bool
+//	cli.Flag(&trace, "trace", flag.NoShortHand, "Trace internal state", cli.HiddenFlag[bool]())
+func HiddenFlag[T flag.Flaggable]() FlagOption[T] {
+	return hiddenFlagOpt[T]{}
+}
+
+type flagAliasOpt[T flag.Flaggable] struct{ name string }
+
+//nolint:unused // Satisfies the unexported FlagOption.apply method, staticcheck can't see across the interface.
+func (o flagAliasOpt[T]) apply(cfg *internalflag.Config[T]) error {
+	if o.name == "" {
+		return errors.New("flag alias cannot be empty")
+	}
+
+	cfg.Aliases = append(cfg.Aliases, o.name)
+
+	return nil
+}
+
+// FlagAlias is a [FlagOption] that adds another long name the flag may be given by, e.g. for
+// alternative spellings or to keep a legacy name working. Aliases follow the same rules
+// as flag names, and are shown alongside the flag's name in help text.
+//
+// It may be given more than once to add multiple aliases.
+//
+//	var colour string
+//	cli.Flag(&colour, "colour", 'c', "Colour of the output", cli.FlagAlias[string]("color"))
+func FlagAlias[T flag.Flaggable](name string) FlagOption[T] {
+	return flagAliasOpt[T]{name: name}
+}
+
 // anyDuplicates checks the list of commands for ones with duplicate names, if a duplicate
 // is found, it's name and true are returned, else "", false.
 func anyDuplicates(cmds ...*Command) (string, bool) {
@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"go.followtheprocess.codes/cli/internal/override"
+	"go.followtheprocess.codes/cli/internal/pager"
+)
+
+//nolint:gochecknoinits // Installs the clitest hooks without widening the public API
+func init() {
+	override.Apply = applyOverride
+	override.EnvVars = envVars
+}
+
+// applyOverride implements [override.Apply].
+func applyOverride(c any, cfg override.Config) error {
+	cmd, ok := c.(*Command)
+	if !ok || cmd == nil {
+		return fmt.Errorf("override: expected a non-nil *cli.Command, got %T", c)
+	}
+
+	if cmd.parent != nil {
+		return errors.New("override: must be applied to the root command")
+	}
+
+	if cfg.Stdin != nil {
+		cmd.stdin = cfg.Stdin
+	}
+
+	if cfg.Stdout != nil {
+		cmd.stdout = cfg.Stdout
+	}
+
+	if cfg.Stderr != nil {
+		cmd.stderr = cfg.Stderr
+	}
+
+	if cfg.Interactive {
+		cmd.interactive = true
+	}
+
+	if cfg.Args != nil {
+		cmd.rawArgs = cfg.Args
+	}
+
+	return nil
+}
+
+// envVars implements [override.EnvVars].
+func envVars(c any) []string {
+	cmd, ok := c.(*Command)
+	if !ok || cmd == nil {
+		return nil
+	}
+
+	var names []string
+
+	var walk func(cmd *Command)
+
+	walk = func(cmd *Command) {
+		for _, fl := range cmd.flagSet().All() {
+			if env := fl.EnvVar(); env != "" && !slices.Contains(names, env) {
+				names = append(names, env)
+			}
+		}
+
+		for _, subcommand := range cmd.subcommands {
+			// A lazy command that fails to build has no flags, the error
+			// is reported when it's executed
+			if built, err := subcommand.resolve(); err == nil {
+				walk(built)
+			}
+		}
+	}
+
+	walk(cmd)
+
+	// cli's own variables would change the behaviour of any command
+	names = append(names, describeEnvVar)
+	names = append(names, pager.EnvVars(cmd.root().name)...)
+
+	slices.Sort(names)
+	names = slices.Compact(names)
+
+	return names
+}
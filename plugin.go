@@ -0,0 +1,225 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+
+	"go.followtheprocess.codes/hue/tabwriter"
+
+	"go.followtheprocess.codes/cli/internal/style"
+)
+
+// pluginsCommandName is the name of the subcommand added by the [Plugins] option.
+const pluginsCommandName = "plugins"
+
+//nolint:gochecknoglobals // Caching the styled title.
+var pluginsTitle = style.Title.Text("Plugins")
+
+// plugin is an external executable on $PATH that extends a command.
+type plugin struct {
+	name string // The name it is invoked by e.g. "foo" for "mytool-foo"
+	path string // Absolute path to the executable
+}
+
+// newPluginsCommand builds the subcommand added by the [Plugins] option.
+func newPluginsCommand() (*Command, error) {
+	list := func() (*Command, error) {
+		return New(
+			"list",
+			Short("List the plugins found on $PATH"),
+			Run(runPluginsList),
+		)
+	}
+
+	return New(
+		pluginsCommandName,
+		Short("Manage external plugins"),
+		SubCommands(list),
+	)
+}
+
+// runPluginsList is the run function for the 'plugins list' subcommand.
+func runPluginsList(_ context.Context, cmd *Command) error {
+	// cmd is 'list', its parent is 'plugins' and the command with plugins is above that
+	if cmd.parent == nil || cmd.parent.parent == nil {
+		// Should be impossible, list is only ever added beneath plugins
+		return fmt.Errorf("%s command has no parent", pluginsCommandName)
+	}
+
+	owner := cmd.parent.parent
+
+	plugins := discoverPlugins(owner)
+	if len(plugins) == 0 {
+		fmt.Fprintf(cmd.Stderr(), "No plugins found, plugins are executables on $PATH named %s-<name>\n", owner.pluginPrefix)
+
+		return nil
+	}
+
+	tw := style.Tabwriter(cmd.Stdout())
+	for _, p := range plugins {
+		fmt.Fprintf(tw, "%s\t%s\n", p.name, p.path)
+	}
+
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("could not format plugins: %w", err)
+	}
+
+	return nil
+}
+
+// splitPlugin looks for a plugin invocation in args, the arguments for cmd left over
+// once the subcommand has been resolved.
+//
+// If the first positional argument names one of cmd's plugins, it returns the path to
+// the plugin's executable, the arguments before the plugin name (which are still cmd's
+// to parse) and the arguments after it (which are passed to the plugin). Otherwise path
+// is empty and args are returned untouched.
+func splitPlugin(cmd *Command, args []string) (path string, cmdArgs, pluginArgs []string) {
+	if cmd.pluginPrefix == "" {
+		return "", args, nil
+	}
+
+	idx, ok := firstNonFlagArg(cmd, args)
+	if !ok || !validPluginName(args[idx]) {
+		return "", args, nil
+	}
+
+	path, err := exec.LookPath(cmd.pluginPrefix + "-" + args[idx])
+	if err != nil {
+		return "", args, nil
+	}
+
+	return path, args[:idx], args[idx+1:]
+}
+
+// runPlugin runs the plugin executable at path with args, connected to cmd's stdio.
+//
+// The plugin is killed if ctx is cancelled. If the plugin exits with a non-zero
+// status, the returned error wraps an [*exec.ExitError] reporting its exit code.
+func runPlugin(ctx context.Context, cmd *Command, path string, args []string) error {
+	plugin := exec.CommandContext(ctx, path, args...)
+	plugin.Stdin = cmd.Stdin()
+	plugin.Stdout = cmd.Stdout()
+	plugin.Stderr = cmd.Stderr()
+
+	if err := plugin.Run(); err != nil {
+		return fmt.Errorf("plugin %s: %w", filepath.Base(path), err)
+	}
+
+	return nil
+}
+
+// discoverPlugins searches $PATH for cmd's plugins, returning them sorted by name.
+//
+// Like the shell, the first executable found for a name wins. Plugins with the same
+// name as one of cmd's subcommands can never be run so are left out.
+func discoverPlugins(cmd *Command) []plugin {
+	if cmd.pluginPrefix == "" {
+		return nil
+	}
+
+	prefix := cmd.pluginPrefix + "-"
+	seen := make(map[string]bool)
+
+	var plugins []plugin
+
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if dir == "" {
+			// An empty entry means the current directory, which exec.LookPath refuses
+			continue
+		}
+
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range entries {
+			name, ok := strings.CutPrefix(pluginName(entry.Name()), prefix)
+			if !ok || seen[name] || !validPluginName(name) || findSubCommand(cmd, name) != nil {
+				continue
+			}
+
+			path := filepath.Join(dir, entry.Name())
+			if !executable(path) {
+				continue
+			}
+
+			seen[name] = true
+
+			plugins = append(plugins, plugin{name: name, path: path})
+		}
+	}
+
+	slices.SortFunc(plugins, func(a, b plugin) int {
+		return strings.Compare(a.name, b.name)
+	})
+
+	return plugins
+}
+
+// writePlugins writes the plugins block to the help text string builder, nothing is
+// written if there are no plugins.
+func writePlugins(cmd *Command, s *strings.Builder, tw *tabwriter.Writer) error {
+	plugins := discoverPlugins(cmd)
+	if len(plugins) == 0 {
+		return nil
+	}
+
+	s.WriteByte('\n')
+	s.WriteString(pluginsTitle)
+	s.WriteString(":\n\n")
+
+	style.ResetTabwriter(tw, s)
+
+	for _, p := range plugins {
+		fmt.Fprintf(tw, "  %s\tRun the %s-%s plugin\n", style.Bold.Text(p.name), cmd.pluginPrefix, p.name)
+	}
+
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("could not format plugins: %w", err)
+	}
+
+	return nil
+}
+
+// validPluginName reports whether name could be a plugin, ruling out anything that
+// would make exec.LookPath treat it as a path rather than searching $PATH.
+func validPluginName(name string) bool {
+	return name != "" && !strings.ContainsAny(name, `/\`)
+}
+
+// pluginName strips any executable extension from a file name, only relevant on
+// Windows where executables are identified by extension.
+func pluginName(file string) string {
+	if runtime.GOOS != "windows" {
+		return file
+	}
+
+	ext := filepath.Ext(file)
+	if slices.Contains([]string{".exe", ".bat", ".cmd", ".com"}, strings.ToLower(ext)) {
+		return strings.TrimSuffix(file, ext)
+	}
+
+	return file
+}
+
+// executable reports whether the file at path can be run as a plugin.
+func executable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return false
+	}
+
+	if runtime.GOOS == "windows" {
+		return pluginName(info.Name()) != info.Name()
+	}
+
+	return info.Mode().Perm()&0o111 != 0
+}
@@ -0,0 +1,306 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"golang.org/x/term"
+
+	"go.followtheprocess.codes/cli/internal/arg"
+	"go.followtheprocess.codes/cli/internal/flag"
+	"go.followtheprocess.codes/cli/internal/format"
+)
+
+// noInputFlag is the name of the flag automatically added to commands with prompts
+// to turn them off.
+const noInputFlag = "no-input"
+
+// promptable is anything that can be prompted for, i.e. flags and arguments.
+type promptable interface {
+	// Usage is the question to ask.
+	Usage() string
+
+	// Type determines the kind of prompt, bools get a yes/no confirmation.
+	Type() string
+
+	// Default is shown as the answer if the user gives none.
+	Default() string
+
+	// Choices, if non-empty, turns the prompt into a select.
+	Choices() []string
+
+	// Set is called with the answer.
+	Set(str string) error
+}
+
+// promptedFlag is a flag being prompted for, its answer is set through the flag set
+// in the same way as a value given on the command line.
+type promptedFlag struct {
+	flag.Value
+
+	set *flag.Set
+}
+
+// Set sets the flag from the answer to its prompt.
+func (p promptedFlag) Set(str string) error {
+	return p.set.SetPrompted(p.Name(), str)
+}
+
+// prompter asks the user for values interactively.
+type prompter struct {
+	in  io.Reader // Where answers are read from, the command's stdin
+	out io.Writer // Where questions are written to, the command's stderr
+}
+
+// newPrompter returns a prompter for cmd, or nil if cmd should not prompt
+// because the user passed --no-input or stdin is not interactive.
+func newPrompter(cmd *Command) *prompter {
+	if cmd.noInput || !interactive(cmd) {
+		return nil
+	}
+
+	return &prompter{in: cmd.Stdin(), out: cmd.Stderr()}
+}
+
+// hasPrompts reports whether any of cmd's flags or arguments prompt for their value.
+func hasPrompts(cmd *Command) bool {
+	for _, f := range cmd.flags.All() {
+		if f.Prompt() {
+			return true
+		}
+	}
+
+	for _, argument := range cmd.args {
+		if argument.Prompt() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// promptFlags prompts for the value of every flag on cmd that asks for a prompt but
+// was not given on the command line or by its environment variable.
+func promptFlags(cmd *Command) error {
+	p := newPrompter(cmd)
+	if p == nil {
+		return nil
+	}
+
+	set := cmd.flagSet()
+
+	for name, f := range set.Sorted() {
+		if !f.Prompt() || set.Changed(name) {
+			continue
+		}
+
+		if _, err := p.ask(promptedFlag{Value: f, set: set}, f.Sensitive(), f.Required()); err != nil {
+			return fmt.Errorf("could not prompt for flag --%s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// askForArg prompts for the value of argument, reporting whether it was answered.
+func askForArg(cmd *Command, argument arg.Value) (answered bool, err error) {
+	p := newPrompter(cmd)
+	if p == nil {
+		return false, nil
+	}
+
+	answered, err = p.ask(argument, false, argument.Default() == "")
+	if err != nil {
+		return false, fmt.Errorf("could not prompt for argument %q: %w", argument.Name(), err)
+	}
+
+	return answered, nil
+}
+
+// ask prompts the user for a value and sets it on v, asking again if the
+// answer is invalid.
+//
+// If the user gives no answer (an empty line, or EOF) the value is left as it
+// was and answered is false, unless the value is required in which case they are
+// asked again until they answer or stdin runs out.
+func (p *prompter) ask(v promptable, secret, required bool) (answered bool, err error) {
+	choices := v.Choices()
+	confirm := v.Type() == format.TypeBool
+
+	for {
+		p.question(v, choices, confirm, secret)
+
+		answer, err := p.readAnswer(secret)
+		if errors.Is(err, io.EOF) {
+			// No more input, the user can't answer so move on
+			fmt.Fprintln(p.out)
+
+			return false, nil
+		}
+
+		if err != nil {
+			return false, err
+		}
+
+		answer = strings.TrimSpace(answer)
+		if answer == "" {
+			if required {
+				fmt.Fprintln(p.out, "  A value is required")
+
+				continue
+			}
+
+			return false, nil
+		}
+
+		switch {
+		case confirm:
+			answer, err = parseConfirm(answer)
+		case len(choices) != 0:
+			answer = parseChoice(answer, choices)
+		}
+
+		if err == nil {
+			err = v.Set(answer)
+		}
+
+		if err != nil {
+			fmt.Fprintf(p.out, "  %v\n", err)
+
+			continue
+		}
+
+		return true, nil
+	}
+}
+
+// question writes the question for v to the user.
+func (p *prompter) question(v promptable, choices []string, confirm, secret bool) {
+	s := &strings.Builder{}
+	s.WriteString("? ")
+	s.WriteString(v.Usage())
+
+	switch {
+	case confirm:
+		if v.Default() == format.True {
+			s.WriteString(" [Y/n]")
+		} else {
+			s.WriteString(" [y/N]")
+		}
+	case len(choices) != 0:
+		s.WriteString("\n")
+
+		for i, choice := range choices {
+			fmt.Fprintf(s, "  %d) %s\n", i+1, choice)
+		}
+
+		fmt.Fprintf(s, "Choose 1-%d", len(choices))
+	}
+
+	if def := v.Default(); def != "" && !confirm && !secret {
+		fmt.Fprintf(s, " (%s)", def)
+	}
+
+	s.WriteString(": ")
+
+	fmt.Fprint(p.out, s.String())
+}
+
+// readAnswer reads a single line answer from the user. If the answer is secret and
+// stdin is a terminal, it is read without echoing it back.
+func (p *prompter) readAnswer(secret bool) (string, error) {
+	if f, ok := p.in.(fileDescriptor); ok && secret && term.IsTerminal(int(f.Fd())) {
+		password, err := term.ReadPassword(int(f.Fd()))
+		// The user's newline isn't echoed either
+		fmt.Fprintln(p.out)
+
+		return string(password), err
+	}
+
+	return readLine(p.in)
+}
+
+// readLine reads a single line from r, without the line ending.
+//
+// It deliberately reads one byte at a time rather than buffering, so that
+// anything after the line is left in r for whoever reads it next, e.g. the
+// command's run function.
+func readLine(r io.Reader) (string, error) {
+	var (
+		line []byte
+		buf  [1]byte
+	)
+
+	for {
+		n, err := r.Read(buf[:])
+		if n == 1 {
+			if buf[0] == '\n' {
+				return strings.TrimSuffix(string(line), "\r"), nil
+			}
+
+			line = append(line, buf[0])
+		}
+
+		if errors.Is(err, io.EOF) && len(line) != 0 {
+			// Last line with no newline
+			return string(line), nil
+		}
+
+		if err != nil {
+			return "", err
+		}
+	}
+}
+
+// parseConfirm parses a yes/no answer into "true" or "false".
+func parseConfirm(answer string) (string, error) {
+	switch strings.ToLower(answer) {
+	case "y", "yes", format.True:
+		return format.True, nil
+	case "n", "no", format.False:
+		return format.False, nil
+	default:
+		return "", fmt.Errorf("please answer yes or no, got %q", answer)
+	}
+}
+
+// parseChoice turns an answer to a select into the choice it refers to, answers
+// may be the number of the choice or the choice itself.
+func parseChoice(answer string, choices []string) string {
+	if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(choices) {
+		return choices[n-1]
+	}
+
+	return answer
+}
+
+// fileDescriptor is a reader backed by a file descriptor, such as an [os.File].
+type fileDescriptor interface {
+	Fd() uintptr
+}
+
+// interactive reports whether cmd's stdin is something a user can type answers into.
+//
+// Stdin must be a terminal, so piped or redirected input is not interactive and nor
+// is any reader that isn't backed by a file, unless the [Interactive] option says
+// otherwise e.g. for scripted answers in tests.
+func interactive(cmd *Command) bool {
+	stdin := cmd.Stdin()
+	if stdin == nil {
+		return false
+	}
+
+	if cmd.root().interactive {
+		return true
+	}
+
+	f, ok := stdin.(fileDescriptor)
+	if !ok {
+		return false
+	}
+
+	return term.IsTerminal(int(f.Fd()))
+}
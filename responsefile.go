@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"go.followtheprocess.codes/cli/internal/shell"
+)
+
+// maxResponseFileDepth is how deeply response files may reference other response files.
+const maxResponseFileDepth = 10
+
+// expandResponseFiles replaces any argument of the form "@path" with the arguments
+// contained in the file at path, recursively.
+//
+// Arguments after a "--" terminator are left alone, as is a lone "@".
+func expandResponseFiles(args []string) ([]string, error) {
+	return expandResponseFileArgs(args, nil)
+}
+
+// expandResponseFileArgs implements expandResponseFiles, stack is the chain of
+// response files currently being read.
+func expandResponseFileArgs(args []string, stack []string) ([]string, error) {
+	expanded := make([]string, 0, len(args))
+
+	for i, arg := range args {
+		if arg == "--" {
+			// Everything after the terminator is taken literally
+			expanded = append(expanded, args[i:]...)
+
+			break
+		}
+
+		path, ok := strings.CutPrefix(arg, "@")
+		if !ok || path == "" {
+			expanded = append(expanded, arg)
+
+			continue
+		}
+
+		contents, err := readResponseFile(path, stack)
+		if err != nil {
+			return nil, err
+		}
+
+		expanded = append(expanded, contents...)
+	}
+
+	return expanded, nil
+}
+
+// readResponseFile reads the arguments from the response file at path, expanding any
+// response files referenced within it. The stack is the chain of response files
+// that led to this one.
+//
+// Each line is split into arguments using shell-like quoting rules, so arguments with
+// spaces in them must be quoted, and '#' starts a comment.
+func readResponseFile(path string, stack []string) ([]string, error) {
+	if slices.Contains(stack, path) {
+		return nil, fmt.Errorf("response file %s is included recursively", path)
+	}
+
+	if len(stack) >= maxResponseFileDepth {
+		return nil, fmt.Errorf("response file %s: response files nested too deeply (max %d)", path, maxResponseFileDepth)
+	}
+
+	stack = append(slices.Clip(stack), path)
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read response file %s: %w", path, err)
+	}
+
+	var args []string
+
+	line := 0
+
+	for text := range strings.Lines(string(contents)) {
+		line++
+
+		text = strings.TrimSuffix(strings.TrimSuffix(text, "\n"), "\r")
+
+		words, err := shell.Split(text)
+		if err != nil {
+			return nil, fmt.Errorf("response file %s:%d: %w", path, line, err)
+		}
+
+		args = append(args, words...)
+	}
+
+	// Expanded in one go rather than line by line, so a "--" stops expansion for the
+	// rest of the file and not just the rest of its line
+	args, err = expandResponseFileArgs(args, stack)
+	if err != nil {
+		return nil, fmt.Errorf("response file %s: %w", path, err)
+	}
+
+	return args, nil
+}
@@ -0,0 +1,342 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"golang.org/x/term"
+
+	"go.followtheprocess.codes/cli/internal/shell"
+)
+
+const (
+	// shellCommandName is the name of the subcommand added by the [Shell] option.
+	shellCommandName = "shell"
+
+	// maxShellHistory is the number of lines kept in the shell's history.
+	maxShellHistory = 500
+)
+
+// shellBuiltins are the commands handled by the shell itself, rather than being
+// dispatched to the command tree, and their descriptions.
+//
+//nolint:gochecknoglobals // Effectively a constant
+var shellBuiltins = [...][2]string{
+	{"exit", "Leave the shell"},
+	{"history", "Show previously entered commands"},
+}
+
+// newShellCommand builds the subcommand added by the [Shell] option.
+func newShellCommand() (*Command, error) {
+	return New(
+		shellCommandName,
+		Short("Start an interactive shell"),
+		Run(runShell),
+	)
+}
+
+// runShell is the run function for the shell subcommand.
+//
+// It reads lines from stdin, splits them into arguments using shell-like quoting
+// rules and executes each one as if it had been typed after the shell's parent on
+// the command line, until stdin runs out or the user types 'exit'.
+func runShell(ctx context.Context, cmd *Command) error {
+	parent := cmd.parent
+	if parent == nil {
+		// Should be impossible, shell is only ever added as a subcommand
+		return fmt.Errorf("%s command has no parent", shellCommandName)
+	}
+
+	root := cmd.root()
+
+	// The arguments needed to get from the root to the shell's parent, so that
+	// 'mytool admin shell' followed by 'users list' runs 'mytool admin users list'
+	var prefix []string
+	for c := parent; c.parent != nil; c = c.parent {
+		prefix = append(prefix, c.name)
+	}
+
+	slices.Reverse(prefix)
+
+	history := &shellHistory{}
+	reader := newLineReader(cmd, commandPath(parent)+"> ", history)
+
+	if t, ok := reader.(*terminalReader); ok {
+		t.term.AutoCompleteCallback = func(line string, pos int, key rune) (string, int, bool) {
+			if key != '\t' || pos != len(line) {
+				return "", 0, false
+			}
+
+			completed, ok := completeShellLine(parent, line)
+
+			return completed, len(completed), ok
+		}
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		line, err := reader.ReadLine()
+		if errors.Is(err, io.EOF) {
+			fmt.Fprintln(cmd.Stderr())
+
+			return nil
+		}
+
+		if err != nil {
+			return fmt.Errorf("could not read from shell: %w", err)
+		}
+
+		words, err := shell.Split(line)
+		if err != nil {
+			fmt.Fprintf(cmd.Stderr(), "error: %v\n", err)
+
+			continue
+		}
+
+		if len(words) == 0 {
+			continue
+		}
+
+		if !reader.recordsHistory() {
+			history.Add(strings.TrimSpace(line))
+		}
+
+		switch words[0] {
+		case "exit", "quit":
+			return nil
+		case "history":
+			for i := history.Len() - 1; i >= 0; i-- {
+				fmt.Fprintf(cmd.Stdout(), "%5d  %s\n", history.Len()-i, history.At(i))
+			}
+
+			continue
+		case shellCommandName:
+			fmt.Fprintln(cmd.Stderr(), "error: already in a shell")
+
+			continue
+		}
+
+		// Every line starts from a clean slate, as if the program had just been launched
+		resetFlags(root)
+
+		if err := root.execute(ctx, slices.Concat(prefix, words)); err != nil {
+			fmt.Fprintf(cmd.Stderr(), "error: %v\n", err)
+		}
+
+		if words[0] == helpCommandName && len(words) == 1 {
+			writeShellBuiltins(cmd.Stderr())
+		}
+	}
+}
+
+// resetFlags resets the flags of every command in the tree beneath cmd to their defaults.
+func resetFlags(cmd *Command) {
+	cmd.flagSet().Reset()
+
+	for _, subcommand := range cmd.subcommands {
+		resetFlags(subcommand)
+	}
+}
+
+// writeShellBuiltins writes the list of shell built-in commands to w, shown
+// after the help text when the user types 'help' in the shell.
+func writeShellBuiltins(w io.Writer) {
+	s := &strings.Builder{}
+	s.WriteString("\nShell Commands:\n\n")
+
+	for _, builtin := range shellBuiltins {
+		fmt.Fprintf(s, "  %-9s%s\n", builtin[0], builtin[1])
+	}
+
+	fmt.Fprint(w, s.String())
+}
+
+// completeShellLine completes the last word in line against the subcommands (or flags,
+// if the word starts with '-') of the command the rest of the line refers to, beneath
+// cmd. It returns the completed line and whether anything was completed.
+func completeShellLine(cmd *Command, line string) (string, bool) {
+	words := strings.Fields(line)
+
+	partial := ""
+	if len(words) != 0 && !strings.HasSuffix(line, " ") {
+		partial = words[len(words)-1]
+		words = words[:len(words)-1]
+	}
+
+	target := cmd
+	for _, word := range words {
+		if sub := findSubCommand(target, word); sub != nil {
+			if built, err := sub.resolve(); err == nil {
+				target = built
+			}
+		}
+	}
+
+	var candidates []string
+
+	if strings.HasPrefix(partial, "-") {
+		for name, fl := range target.flagSet().Sorted() {
+			if fl.Hidden() {
+				continue
+			}
+
+			candidates = append(candidates, "--"+name)
+			for _, alias := range fl.Aliases() {
+				candidates = append(candidates, "--"+alias)
+			}
+		}
+	} else {
+		for _, subcommand := range target.subcommands {
+			candidates = append(candidates, subcommand.name)
+		}
+
+		if target == cmd && len(words) == 0 {
+			for _, builtin := range shellBuiltins {
+				candidates = append(candidates, builtin[0])
+			}
+		}
+	}
+
+	candidates = slices.DeleteFunc(candidates, func(candidate string) bool {
+		return !strings.HasPrefix(candidate, partial) || candidate == shellCommandName
+	})
+
+	if len(candidates) == 0 {
+		return "", false
+	}
+
+	completion := commonPrefix(candidates)
+	if len(candidates) == 1 {
+		// Unambiguous, so finish the word off ready for the next one
+		completion += " "
+	}
+
+	if completion == partial {
+		return "", false
+	}
+
+	return strings.TrimSuffix(line, partial) + completion, true
+}
+
+// commonPrefix returns the longest prefix shared by every string in strs.
+func commonPrefix(strs []string) string {
+	prefix := strs[0]
+
+	for _, str := range strs[1:] {
+		for !strings.HasPrefix(str, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+
+	return prefix
+}
+
+// lineReader reads lines of input for the shell.
+type lineReader interface {
+	// ReadLine reads the next line, returning io.EOF when there are no more.
+	ReadLine() (string, error)
+
+	// recordsHistory reports whether the reader adds lines to the history itself.
+	recordsHistory() bool
+}
+
+// newLineReader returns the lineReader for cmd's stdin, showing prompt before each line.
+//
+// If stdin is a terminal, the reader supports line editing, history with the arrow
+// keys and tab completion, otherwise lines are read as is.
+func newLineReader(cmd *Command, prompt string, history *shellHistory) lineReader {
+	if f, ok := cmd.Stdin().(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		rw := struct {
+			io.Reader
+			io.Writer
+		}{f, cmd.Stderr()}
+
+		t := term.NewTerminal(rw, prompt)
+		t.History = history
+
+		if width, height, err := term.GetSize(int(f.Fd())); err == nil {
+			t.SetSize(width, height) //nolint:errcheck // Not worth failing over, defaults are fine
+		}
+
+		return &terminalReader{term: t, fd: int(f.Fd())}
+	}
+
+	return &plainReader{in: cmd.Stdin(), out: cmd.Stderr(), prompt: prompt}
+}
+
+// terminalReader is a lineReader for an interactive terminal.
+type terminalReader struct {
+	term *term.Terminal
+	fd   int // File descriptor of the terminal
+}
+
+// ReadLine implements lineReader for a terminalReader.
+func (r *terminalReader) ReadLine() (string, error) {
+	// Only in raw mode while reading, so commands run from the shell see a normal terminal
+	state, err := term.MakeRaw(r.fd)
+	if err != nil {
+		return "", fmt.Errorf("could not put terminal in raw mode: %w", err)
+	}
+
+	defer term.Restore(r.fd, state) //nolint:errcheck // Nothing sensible we could do
+
+	return r.term.ReadLine()
+}
+
+// recordsHistory implements lineReader for a terminalReader, the terminal
+// adds each line to its History.
+func (r *terminalReader) recordsHistory() bool {
+	return true
+}
+
+// plainReader is a lineReader for non-interactive input e.g. a pipe or tests.
+type plainReader struct {
+	in     io.Reader
+	out    io.Writer
+	prompt string
+}
+
+// ReadLine implements lineReader for a plainReader.
+func (r *plainReader) ReadLine() (string, error) {
+	fmt.Fprint(r.out, r.prompt)
+
+	return readLine(r.in)
+}
+
+// recordsHistory implements lineReader for a plainReader.
+func (r *plainReader) recordsHistory() bool {
+	return false
+}
+
+// shellHistory is a bounded history of the lines entered into the shell,
+// it implements [term.History].
+type shellHistory struct {
+	entries []string // Oldest first
+}
+
+// Add adds an entry to the history, dropping the oldest if it is full.
+func (h *shellHistory) Add(entry string) {
+	if len(h.entries) == maxShellHistory {
+		h.entries = h.entries[1:]
+	}
+
+	h.entries = append(h.entries, entry)
+}
+
+// Len returns the number of entries in the history.
+func (h *shellHistory) Len() int {
+	return len(h.entries)
+}
+
+// At returns an entry from the history, 0 being the most recent.
+func (h *shellHistory) At(idx int) string {
+	return h.entries[len(h.entries)-1-idx]
+}
@@ -0,0 +1,142 @@
+// Package spec defines a declarative description of a command line interface: its
+// commands, flags, arguments and examples.
+//
+// A spec is what the cli-gen tool generates code from, and what [cli.Command.Describe]
+// produces from an existing command tree, so an interface can be reviewed (and changes
+// to it diffed) without reading any Go. Specs are usually written in YAML or JSON:
+//
+//	name: mytool
+//	short: A tool for doing things
+//	commands:
+//	  - name: serve
+//	    short: Run the server
+//	    flags:
+//	      - name: port
+//	        short: p
+//	        type: int
+//	        usage: Port to listen on
+//	        default: "8080"
+//	    args:
+//	      - name: dir
+//	        type: string
+//	        usage: Directory to serve
+//
+// [cli.Command.Describe]: https://pkg.go.dev/go.followtheprocess.codes/cli#Command.Describe
+package spec
+
+// Command describes a single command and, recursively, its subcommands.
+type Command struct {
+	// Name is the name of the command as typed on the command line.
+	Name string `json:"name" yaml:"name"`
+
+	// Short is the one line description of the command.
+	Short string `json:"short,omitempty" yaml:"short,omitempty"`
+
+	// Long is the full description of the command.
+	Long string `json:"long,omitempty" yaml:"long,omitempty"`
+
+	// Group is the title of the section the command is listed under in its parent's help.
+	Group string `json:"group,omitempty" yaml:"group,omitempty"`
+
+	// Examples are the usage examples shown in the command's help.
+	Examples []Example `json:"examples,omitempty" yaml:"examples,omitempty"`
+
+	// Flags are the command's flags, not including the built in ones e.g. --help. Described
+	// commands list them in name order, as in help text.
+	Flags []Flag `json:"flags,omitempty" yaml:"flags,omitempty"`
+
+	// Args are the command's positional arguments, in order.
+	Args []Arg `json:"args,omitempty" yaml:"args,omitempty"`
+
+	// Commands are the command's subcommands, not including the built in ones e.g. help.
+	Commands []Command `json:"commands,omitempty" yaml:"commands,omitempty"`
+}
+
+// Flag describes a command line flag.
+type Flag struct {
+	// Name is the name of the flag e.g. "force" for --force.
+	Name string `json:"name" yaml:"name"`
+
+	// Aliases are other long names the flag may be given by e.g. "color" for "colour".
+	Aliases []string `json:"aliases,omitempty" yaml:"aliases,omitempty"`
+
+	// Short is the single character shorthand for the flag e.g. "f" for -f, or
+	// empty if it has none.
+	Short string `json:"short,omitempty" yaml:"short,omitempty"`
+
+	// Type is the type of the flag, as shown in help text e.g. "int", "duration"
+	// or "[]string".
+	Type string `json:"type" yaml:"type"`
+
+	// Usage is the one line description of the flag.
+	Usage string `json:"usage,omitempty" yaml:"usage,omitempty"`
+
+	// Default is the default value of the flag, formatted as it would be typed on the
+	// command line. Slice defaults are comma separated, items containing a comma, a
+	// quote or surrounding whitespace are double quoted e.g. `"a, b",c`. Empty means
+	// the zero value.
+	Default string `json:"default,omitempty" yaml:"default,omitempty"`
+
+	// NoArgDefault is the value the flag takes when given without one e.g. --colour
+	// rather than --colour=never, empty if a value is always required.
+	NoArgDefault string `json:"noArgDefault,omitempty" yaml:"noArgDefault,omitempty"`
+
+	// Delimiter splits each value given to a slice flag into items e.g. "," for
+	// --items one,two, empty if values aren't split.
+	Delimiter string `json:"delimiter,omitempty" yaml:"delimiter,omitempty"`
+
+	// ReplaceDefault is whether values given to a slice flag replace its default,
+	// rather than being added to it.
+	ReplaceDefault bool `json:"replaceDefault,omitempty" yaml:"replaceDefault,omitempty"`
+
+	// Env is the name of an environment variable the flag may be set by.
+	Env string `json:"env,omitempty" yaml:"env,omitempty"`
+
+	// Group is the title of the section the flag is listed under in help.
+	Group string `json:"group,omitempty" yaml:"group,omitempty"`
+
+	// Choices are the only values the flag may take, if any.
+	Choices []string `json:"choices,omitempty" yaml:"choices,omitempty"`
+
+	// Required is whether the flag must be given a value.
+	Required bool `json:"required,omitempty" yaml:"required,omitempty"`
+
+	// Sensitive is whether the flag's value is masked in help text and errors.
+	Sensitive bool `json:"sensitive,omitempty" yaml:"sensitive,omitempty"`
+
+	// Deprecated is the message shown when the flag is used, empty if it's not deprecated.
+	Deprecated string `json:"deprecated,omitempty" yaml:"deprecated,omitempty"`
+
+	// ReplacedBy is the name of the flag any values given to this one are forwarded to.
+	ReplacedBy string `json:"replacedBy,omitempty" yaml:"replacedBy,omitempty"`
+
+	// Hidden is whether the flag is left out of help text and completion.
+	Hidden bool `json:"hidden,omitempty" yaml:"hidden,omitempty"`
+}
+
+// Arg describes a positional argument.
+type Arg struct {
+	// Name is the name of the argument.
+	Name string `json:"name" yaml:"name"`
+
+	// Type is the type of the argument, as shown in help text e.g. "string".
+	Type string `json:"type" yaml:"type"`
+
+	// Usage is the one line description of the argument.
+	Usage string `json:"usage,omitempty" yaml:"usage,omitempty"`
+
+	// Default is the default value of the argument, empty if the argument is required.
+	Default string `json:"default,omitempty" yaml:"default,omitempty"`
+
+	// Choices are the only values the argument may take, if any.
+	Choices []string `json:"choices,omitempty" yaml:"choices,omitempty"`
+}
+
+// Example is a usage example shown in a command's help.
+type Example struct {
+	// Comment describes what the example does.
+	Comment string `json:"comment" yaml:"comment"`
+
+	// Command is the example command line.
+	Command string `json:"command" yaml:"command"`
+}
@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+)
+
+// Between returns a validator, for use with [Validate] or [ArgValidate], that only allows
+// values from lower to upper inclusive.
+//
+//	cli.Flag(&port, "port", 'p', "Port to listen on", cli.Validate(cli.Between(1, 65535)))
+func Between[T cmp.Ordered](lower, upper T) func(T) error {
+	return func(value T) error {
+		if value < lower || value > upper {
+			return fmt.Errorf("must be between %v and %v", lower, upper)
+		}
+
+		return nil
+	}
+}
+
+// Min returns a validator, for use with [Validate] or [ArgValidate], that only allows
+// values greater than or equal to minimum.
+//
+//	cli.Flag(&timeout, "timeout", 't', "Request timeout", cli.Validate(cli.Min(time.Second)))
+func Min[T cmp.Ordered](minimum T) func(T) error {
+	return func(value T) error {
+		if value < minimum {
+			return fmt.Errorf("must be at least %v", minimum)
+		}
+
+		return nil
+	}
+}
+
+// Max returns a validator, for use with [Validate] or [ArgValidate], that only allows
+// values less than or equal to maximum.
+//
+//	cli.Flag(&workers, "workers", 'w', "Number of workers", cli.Validate(cli.Max(64)))
+func Max[T cmp.Ordered](maximum T) func(T) error {
+	return func(value T) error {
+		if value > maximum {
+			return fmt.Errorf("must be at most %v", maximum)
+		}
+
+		return nil
+	}
+}
+
+// Matches returns a validator, for use with [Validate] or [ArgValidate], that only allows
+// strings matching re. Use ^ and $ in the pattern to match the whole value.
+//
+//	cli.Flag(&name, "name", 'n', "Resource name", cli.Validate(cli.Matches(regexp.MustCompile(`^[a-z][a-z0-9-]*$`))))
+func Matches(re *regexp.Regexp) func(string) error {
+	return func(value string) error {
+		if re == nil {
+			return errors.New("nil regexp")
+		}
+
+		if !re.MatchString(value) {
+			return fmt.Errorf("must match %s", re)
+		}
+
+		return nil
+	}
+}
+
+// NonEmpty is a validator, for use with [Validate] or [ArgValidate], that rejects the
+// empty string.
+//
+//	cli.Flag(&name, "name", 'n', "Resource name", cli.Validate(cli.NonEmpty))
+func NonEmpty(value string) error {
+	if value == "" {
+		return errors.New("must not be empty")
+	}
+
+	return nil
+}
+
+// FileExists is a validator, for use with [Validate] or [ArgValidate], that only allows
+// paths to files that exist.
+//
+//	cli.Arg(&config, "config", "Path to the config file", cli.ArgValidate(cli.FileExists))
+func FileExists(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return statError(err)
+	}
+
+	if info.IsDir() {
+		return errors.New("is a directory")
+	}
+
+	return nil
+}
+
+// DirExists is a validator, for use with [Validate] or [ArgValidate], that only allows
+// paths to directories that exist.
+//
+//	cli.Arg(&dir, "dir", "Directory to serve", cli.ArgValidate(cli.DirExists))
+func DirExists(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return statError(err)
+	}
+
+	if !info.IsDir() {
+		return errors.New("not a directory")
+	}
+
+	return nil
+}
+
+// statError strips the operation and path from an [os.Stat] error, as the parse error
+// it ends up in already shows the path. It's still matchable with e.g. [fs.ErrNotExist].
+func statError(err error) error {
+	var pathErr *os.PathError
+	if errors.As(err, &pathErr) {
+		return pathErr.Err
+	}
+
+	return err
+}